@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/project/api"
+	"github.com/spf13/cobra"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a feature to a generated project",
+	Long: `Add a feature to a project sova generated. Run it in the root of the
+project, or name the project with --dir.`,
+}
+
+var addOpenAPICmd = &cobra.Command{
+	Use:   "openapi [spec]",
+	Short: "Generate the API of a project from an OpenAPI 3 spec",
+	Long: `Generate the API of an api project from an OpenAPI 3 spec in YAML or JSON:
+
+  - internal/openapi holds the models, a handler interface per operation,
+    the routes validating requests against the spec, and the docs served
+    at /docs. It is regenerated every time; do not edit it.
+  - internal/handlers gets a stub returning 501 Not Implemented for every
+    operation the API type does not implement yet. Existing handlers are
+    never changed.
+
+The spec is copied to api/ and recorded in .sova-project.yaml, so after
+editing it, run 'sova add openapi' without arguments to regenerate.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, _ := cmd.Flags().GetString("dir")
+		specPath := ""
+		if len(args) > 0 {
+			specPath = args[0]
+		}
+		return api.AddOpenAPI(dir, specPath, events.NewTextSink(cmd.OutOrStdout(), os.Stderr))
+	},
+}
+
+func init() {
+	addCmd.PersistentFlags().String("dir", ".", "root directory of the project")
+
+	addCmd.AddCommand(addOpenAPICmd)
+	rootCmd.AddCommand(addCmd)
+}
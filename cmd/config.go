@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Show and change the defaults in ~/.sova.yaml",
+	Long: `Show and change the settings sova uses for new projects.
+
+Settings are read from ~/.sova.yaml (or --config, or $SOVA_CONFIG) and can
+be overridden with SOVA_* environment variables. Run 'sova config list' to
+see every setting and its current value.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := config.Current().Get(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Store a setting in the config file",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		key, err := config.LookupKey(args[0])
+		if err != nil {
+			return err
+		}
+
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+
+		if err := config.SetInFile(path, key.Name, args[1]); err != nil {
+			return err
+		}
+
+		PrintSuccess("Set %s to %s in %s", key.Name, args[1], path)
+		if _, ok := os.LookupEnv(key.Env); ok {
+			PrintWarning("%s is set and overrides this value", key.Env)
+		}
+		return nil
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print every setting and its current value",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Current()
+		for _, key := range config.Keys {
+			value, err := cfg.Get(key.Name)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s = %v\n", key.Name, value)
+		}
+		return nil
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/deps"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/spf13/cobra"
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps [template]",
+	Short: "Show the module versions a template pins in go.mod",
+	Long: `Show the modules the go.mod of a new project may require and the
+versions sova pins them to.
+
+Each template lists its modules in deps.yaml with a version constraint.
+'sova deps set' overrides a version in the config file, within that
+constraint. WHEN names the answer that makes a project require the module.`,
+	Example: `  sova deps api
+  sova deps set github.com/gin-gonic/gin v1.10.0
+  sova deps unset github.com/gin-gonic/gin`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg := config.Current()
+		templateName := cfg.Defaults.Template
+		if len(args) > 0 {
+			templateName = args[0]
+		}
+		if err := project.NewTemplateManager().ValidateTemplate(templateName); err != nil {
+			return err
+		}
+
+		catalog, err := project.DependencyCatalog(templateName, cfg)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "MODULE\tVERSION\tCONSTRAINT\tWHEN")
+		for i := range catalog.Dependencies {
+			d := &catalog.Dependencies[i]
+			requirement, err := d.Resolve(cfg.Dependencies)
+			if err != nil {
+				return err
+			}
+
+			version := requirement.Version
+			if requirement.Overridden {
+				version += " (config)"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", d.Module, version, orDash(d.Constraint), orDash(d.When))
+		}
+		return w.Flush()
+	},
+}
+
+var depsSetCmd = &cobra.Command{
+	Use:   "set <module> <version>",
+	Short: "Pin a module version in the config file",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		module, version := args[0], args[1]
+		if _, err := deps.ParseVersion(version); err != nil {
+			return err
+		}
+
+		known := false
+		for _, projectType := range project.ProjectTypes() {
+			catalog, err := project.DependencyCatalog(projectType, config.Current())
+			if err != nil {
+				return err
+			}
+			for i := range catalog.Dependencies {
+				d := &catalog.Dependencies[i]
+				if d.Module != module {
+					continue
+				}
+				known = true
+				if _, err := d.Resolve(map[string]string{module: version}); err != nil {
+					return fmt.Errorf("%v (%s template)", err, projectType)
+				}
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown dependency: %s (see sova deps <template>)", module)
+		}
+
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		if err := config.SetDependency(path, module, version); err != nil {
+			return err
+		}
+		PrintSuccess("Pinned %s to %s in %s", module, version, path)
+		return nil
+	},
+}
+
+var depsUnsetCmd = &cobra.Command{
+	Use:   "unset <module>",
+	Short: "Remove a pinned module version from the config file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := configPath()
+		if err != nil {
+			return err
+		}
+		if err := config.UnsetDependency(path, args[0]); err != nil {
+			return err
+		}
+		PrintSuccess("Removed the pinned version of %s", args[0])
+		return nil
+	},
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	depsCmd.AddCommand(depsSetCmd)
+	depsCmd.AddCommand(depsUnsetCmd)
+	rootCmd.AddCommand(depsCmd)
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/doctor"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor [dir]",
+	Short: "Check a generated project for missing files, modules and settings",
+	Long: `Check a project sova generated against what its template renders for
+the answers recorded in .sova-project.yaml:
+
+  - the directories and files of the template exist
+  - go.mod requires the modules the template uses
+  - .env sets every variable the enabled services read
+  - docker-compose.yml runs the services of the enabled integrations
+  - the local Go toolchain is new enough
+
+Each problem is printed with a fix. sova doctor exits non-zero when a
+check fails; warnings alone do not fail it.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+
+		report, err := doctor.Run(vfs.NewOSFS(dir), config.Current())
+		if err != nil {
+			return err
+		}
+
+		// The report already explains every failure.
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "Checking %s (%s template %s)\n\n", report.Manifest.ProjectName, report.Manifest.Template, report.Manifest.TemplateVersion)
+		for _, result := range report.Results {
+			switch result.Status {
+			case doctor.Pass:
+				fmt.Fprintf(out, "%s %s\n", color.GreenString("✓"), result.Message)
+			case doctor.Warn:
+				fmt.Fprintf(out, "%s %s\n", color.YellowString("!"), result.Message)
+			case doctor.Fail:
+				fmt.Fprintf(out, "%s %s\n", color.RedString("✗"), result.Message)
+			}
+			if result.Fix != "" {
+				fmt.Fprintf(out, "    fix: %s\n", result.Fix)
+			}
+		}
+
+		if n := report.Failures(); n > 0 {
+			fmt.Fprintln(out)
+			return fmt.Errorf("found %d %s", n, plural(n, "problem", "problems"))
+		}
+		fmt.Fprintln(out)
+		PrintSuccess("No problems found")
+		return nil
+	},
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
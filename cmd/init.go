@@ -3,6 +3,10 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/project"
 	"github.com/go-sova/sova-cli/internal/project/api"
 	"github.com/go-sova/sova-cli/internal/project/cli"
 	"github.com/go-sova/sova-cli/pkg/questions"
@@ -17,48 +21,76 @@ This command will guide you through the project setup process.
 If you don't provide a project name, you'll be prompted to enter one.
 You can choose between different project types:
   - api: A Go API project with clean architecture
-  - cli: A Go CLI project with clean architecture`,
+  - cli: A Go CLI project with clean architecture
+
+Run 'sova init api [project-name]' or 'sova init cli [project-name]' to skip
+the project type prompt, or --preset to start from a saved preset (see
+'sova presets'). Use --archive to write the project to a zip or
+tar.gz archive ("-" for stdout) instead of the working tree. Use --output json
+to follow the generation as newline-delimited JSON events.`,
 	Args: cobra.MaximumNArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		var projectName string
 		var projectType string
 		var err error
 
+		outputFormat, _ := cmd.Flags().GetString("output")
+		format, err := events.ParseFormat(outputFormat)
+		if err != nil {
+			return err
+		}
+		archivePath, _ := cmd.Flags().GetString("archive")
+		// Failures before the project type is known are reported here;
+		// the api and cli commands report their own.
+		sink := project.EventSink(format, archivePath)
+
+		var askOpts []survey.AskOpt
+		if archivePath == "-" || format == events.JSON {
+			askOpts = append(askOpts, questions.WithStderr())
+		}
+
 		if len(args) > 0 {
 			projectName = args[0]
 		} else {
-			projectName, err = questions.AskProjectName()
+			projectName, err = questions.AskProjectName(askOpts...)
 			if err != nil {
-				fmt.Printf("Error: %v\n", err)
-				return
+				return project.Report(sink, projectName, err)
 			}
 		}
 
-		projectType, err = questions.AskProjectType()
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+		if presetName, _ := cmd.Flags().GetString("preset"); presetName != "" {
+			preset, err := project.LoadPreset(presetName)
+			if err != nil {
+				return project.Report(sink, projectName, err)
+			}
+			projectType = preset.Template
+		} else {
+			projectType, err = questions.AskProjectType(project.ProjectTypes(), config.Current().Defaults.Template, askOpts...)
+			if err != nil {
+				return project.Report(sink, projectName, err)
+			}
 		}
 
 		switch projectType {
 		case "api":
-			apiCmd := api.InitCmd
-			apiCmd.SetArgs([]string{projectName})
-			err = apiCmd.Execute()
+			return api.InitCmd.RunE(cmd, []string{projectName})
 		case "cli":
-			cliCmd := cli.InitCmd
-			cliCmd.SetArgs([]string{projectName})
-			err = cliCmd.Execute()
+			return cli.InitCmd.RunE(cmd, []string{projectName})
 		default:
-			err = fmt.Errorf("unsupported project type: %s", projectType)
-		}
-
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+			return project.Report(sink, projectName, fmt.Errorf("unsupported project type: %s", projectType))
 		}
 	},
 }
 
 func init() {
+	initCmd.PersistentFlags().String("archive", "", `write the project to a .zip or .tar.gz archive instead of a directory ("-" for stdout)`)
+	initCmd.PersistentFlags().String("archive-format", "", "archive format: tar.gz or zip (default: inferred from --archive, tar.gz for stdout)")
+
+	initCmd.PersistentFlags().String("output", "text", "progress output: text, or json for newline-delimited events")
+	initCmd.PersistentFlags().String("go-version", "", "Go release the project targets (default: defaults.goVersion, else the local toolchain)")
+	initCmd.PersistentFlags().String("preset", "", "pre-fill answers from a saved preset (see 'sova presets list')")
+
+	initCmd.AddCommand(api.InitCmd)
+	initCmd.AddCommand(cli.InitCmd)
 	rootCmd.AddCommand(initCmd)
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/go-sova/sova-cli/internal/openapi"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+	"github.com/spf13/cobra"
+)
+
+var openapiCmd = &cobra.Command{
+	Use:   "openapi",
+	Short: "Work with the OpenAPI description of an API project",
+}
+
+var openapiExportCmd = &cobra.Command{
+	Use:   "export [dir]",
+	Short: "Describe the routes of an API project as an OpenAPI 3 document",
+	Long: `Describe the routes of an API project as an OpenAPI 3 document in YAML.
+
+The routes registered in internal/routes are followed through their router
+groups, and the handlers they register are read for:
+
+  - path parameters, and query and header parameters read with c.Query,
+    c.DefaultQuery, c.GetQuery, c.QueryArray and c.GetHeader
+  - JSON request bodies bound with c.ShouldBindJSON or c.BindJSON
+  - the status and body of c.JSON, c.Status and similar responses
+
+Structs of the project become component schemas, named after their types
+and keyed by their json tags. The result is a skeleton to refine: parts
+sova cannot infer, such as handlers that are not gin handler functions,
+are listed as warnings.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := "."
+		if len(args) > 0 {
+			dir = args[0]
+		}
+		fsys := vfs.NewOSFS(dir)
+		module, err := project.ModulePath(fsys)
+		if err != nil {
+			return err
+		}
+
+		doc, warnings, err := openapi.Export(fsys, module)
+		if err != nil {
+			return err
+		}
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+		}
+
+		output, _ := cmd.Flags().GetString("output")
+		if output == "" || output == "-" {
+			_, err = cmd.OutOrStdout().Write(doc)
+			return err
+		}
+		if err := os.WriteFile(output, doc, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %v", output, err)
+		}
+		PrintSuccess("OpenAPI document written to %s", output)
+		return nil
+	},
+}
+
+func init() {
+	openapiExportCmd.Flags().StringP("output", "o", "", `write the document to this file instead of stdout ("-" for stdout)`)
+
+	openapiCmd.AddCommand(openapiExportCmd)
+	rootCmd.AddCommand(openapiCmd)
+}
@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/templates"
+	"github.com/spf13/cobra"
+)
+
+var presetsCmd = &cobra.Command{
+	Use:   "presets",
+	Short: "Manage named presets of project answers",
+	Long: `Manage named presets of project answers.
+
+A preset stores the template and the answers of a kind of project you
+create often. 'sova init my-service --preset team-api' starts from those
+answers and only asks the questions the preset leaves open. Presets are
+kept under 'presets' in the config file.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var presetsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List saved presets",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		presets, err := config.LoadPresets(config.Current().File)
+		if err != nil {
+			return err
+		}
+		if len(presets) == 0 {
+			PrintInfo("No presets saved. Create one with 'sova presets save <name> --template <type>'.")
+			return nil
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tTEMPLATE\tPINNED\tANSWERS")
+		for _, preset := range presets {
+			pinned := "-"
+			if preset.TemplateVersion != "" {
+				pinned = preset.TemplateVersion
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", preset.Name, preset.Template, pinned, formatAnswers(preset.Answers))
+		}
+		return w.Flush()
+	},
+}
+
+var presetsSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save a preset",
+	Long: `Save a preset, replacing any preset with the same name.
+
+Answers are given with --set Name=value (multiselect values are comma
+separated). Without --set, every question of the template is asked.
+--pin records the installed template version; sova init then refuses
+the preset once the template changes.`,
+	Example: `  sova presets save team-api --template api --set UsePostgres=true --set UseRedis=true
+  sova presets save worker --template api --pin`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		templateName, _ := cmd.Flags().GetString("template")
+		sets, _ := cmd.Flags().GetStringArray("set")
+		pin, _ := cmd.Flags().GetBool("pin")
+		description, _ := cmd.Flags().GetString("description")
+
+		if templateName == "" {
+			templateName = config.Current().Defaults.Template
+		}
+		if err := project.NewTemplateManager().ValidateTemplate(templateName); err != nil {
+			return err
+		}
+
+		preset := config.Preset{
+			Name:        args[0],
+			Template:    templateName,
+			Description: description,
+		}
+
+		if len(sets) > 0 {
+			answers, err := parseAnswerFlags(templateName, sets)
+			if err != nil {
+				return err
+			}
+			preset.Answers = answers
+		} else {
+			answers, err := questions.AskProjectQuestions(project.TemplateFS(config.Current()), templateName)
+			if err != nil {
+				return fmt.Errorf("failed to get project configuration: %v", err)
+			}
+			preset.Answers = answers.Values
+		}
+
+		if pin {
+			info, err := templates.LoadInfo(templateName)
+			if err != nil {
+				return err
+			}
+			preset.TemplateVersion = info.Version
+		}
+
+		path := config.Current().File
+		if err := config.SavePreset(path, preset); err != nil {
+			return err
+		}
+		PrintSuccess("Saved preset %s to %s", preset.Name, path)
+		return nil
+	},
+}
+
+var presetsDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a preset",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.DeletePreset(config.Current().File, args[0]); err != nil {
+			return err
+		}
+		PrintSuccess("Deleted preset %s", args[0])
+		return nil
+	},
+}
+
+// parseAnswerFlags converts Name=value pairs into answers of the
+// questions of a template.
+func parseAnswerFlags(templateName string, sets []string) (map[string]interface{}, error) {
+	qs, err := questions.ProjectQuestions(project.TemplateFS(config.Current()), templateName)
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make(map[string]interface{}, len(sets))
+	for _, set := range sets {
+		name, value, ok := strings.Cut(set, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q: expected Name=value", set)
+		}
+
+		var question *questions.Question
+		for i := range qs {
+			if qs[i].Name == name {
+				question = &qs[i]
+			}
+		}
+		if question == nil {
+			return nil, fmt.Errorf("unknown answer for %s project: %s", templateName, name)
+		}
+
+		parsed, err := question.ParseString(value)
+		if err != nil {
+			return nil, err
+		}
+		answers[name] = parsed
+	}
+	return answers, nil
+}
+
+func formatAnswers(answers map[string]interface{}) string {
+	names := make([]string, 0, len(answers))
+	for name := range answers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		value := answers[name]
+		if list, ok := value.([]interface{}); ok {
+			items := make([]string, len(list))
+			for j, item := range list {
+				items[j] = fmt.Sprint(item)
+			}
+			value = strings.Join(items, ",")
+		}
+		parts[i] = fmt.Sprintf("%s=%v", name, value)
+	}
+	return strings.Join(parts, " ")
+}
+
+func init() {
+	presetsSaveCmd.Flags().String("template", "", "template the preset is for (default: defaults.template)")
+	presetsSaveCmd.Flags().StringArray("set", nil, "answer as Name=value, may be repeated")
+	presetsSaveCmd.Flags().Bool("pin", false, "pin the preset to the installed template version")
+	presetsSaveCmd.Flags().String("description", "", "short description of the preset")
+
+	presetsCmd.AddCommand(presetsListCmd)
+	presetsCmd.AddCommand(presetsSaveCmd)
+	presetsCmd.AddCommand(presetsDeleteCmd)
+	rootCmd.AddCommand(presetsCmd)
+}
@@ -6,14 +6,21 @@ import (
 	"os"
 
 	"github.com/fatih/color"
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/pkg/utils"
 	"github.com/go-sova/sova-cli/templates"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	cfgFile string
-	verbose bool
+	cfgFile   string
+	verbose   bool
+	debug     bool
+	logFormat string
+	logFile   string
+	// logOutput is the --log-file file, closed once the command ran
+	logOutput *os.File
 )
 
 var rootCmd = &cobra.Command{
@@ -24,6 +31,13 @@ with predefined templates and structures.
 
 Available Commands:
   init        Initialize a new project with your desired settings
+  add         Add a feature, such as an OpenAPI spec, to a generated project
+  openapi     Export the routes of an API project as an OpenAPI document
+  config      Show and change the defaults in ~/.sova.yaml
+  presets     Manage named presets of project answers
+  deps        Show the module versions a template pins in go.mod
+  doctor      Check a generated project for missing files, modules and settings
+  serve       Run an HTTP service that generates projects on request
   version     Display version information
   help        Help about any command
 
@@ -41,21 +55,34 @@ func Execute() error {
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	cobra.OnFinalize(closeLogging)
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.sova.yaml)")
-	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose output")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "enable verbose output (also SOVA_VERBOSE=1)")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "log debug messages of every component (also SOVA_DEBUG=1)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log format: text or json")
+	rootCmd.PersistentFlags().StringVar(&logFile, "log-file", "", "append log messages to this file instead of stderr")
 
 	rootCmd.Flags().BoolP("version", "V", false, "display version information")
 
 	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
+	viper.BindEnv("verbose", "SOVA_VERBOSE")
+	viper.BindPFlag("debug", rootCmd.PersistentFlags().Lookup("debug"))
+	viper.BindEnv("debug", "SOVA_DEBUG")
 
 	// Initialize template filesystem
 	templateFS = templates.GetTemplateFS()
 }
 
 func initConfig() {
+	initLogging()
+
+	if cfgFile == "" {
+		cfgFile = os.Getenv("SOVA_CONFIG")
+	}
+
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
 	} else {
@@ -70,10 +97,74 @@ func initConfig() {
 	viper.AutomaticEnv()
 
 	if err := viper.ReadInConfig(); err == nil {
-		if verbose {
-			fmt.Fprintln(os.Stderr, "Using config file:", viper.ConfigFileUsed())
+		utils.DefaultLogger.Debug("Using config file: %s", viper.ConfigFileUsed())
+	}
+
+	cfg, err := config.Load(viper.GetViper())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid configuration: %v\n", err)
+	}
+	if cfg.File, err = configPath(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if cfg.Dependencies, err = config.LoadDependencies(cfg.File); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: invalid configuration: %v\n", err)
+	}
+	config.SetCurrent(cfg)
+}
+
+// initLogging applies --verbose, --debug, SOVA_VERBOSE, SOVA_DEBUG,
+// --log-format and --log-file to the loggers of every component.
+func initLogging() {
+	opts := utils.LoggingOptions()
+	if viper.GetBool("verbose") || viper.GetBool("debug") {
+		opts.Level = utils.Debug
+	}
+
+	format, err := utils.ParseLogFormat(logFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	opts.Format = format
+
+	if logFile != "" {
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to open log file: %v\n", err)
+		} else {
+			logOutput = f
+			opts.Output = f
 		}
 	}
+
+	utils.ConfigureLogging(opts)
+}
+
+// closeLogging flushes and closes the --log-file file, sending later log
+// messages back to stderr.
+func closeLogging() {
+	if logOutput == nil {
+		return
+	}
+	opts := utils.LoggingOptions()
+	opts.Output = os.Stderr
+	utils.ConfigureLogging(opts)
+
+	if err := logOutput.Sync(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to flush log file: %v\n", err)
+	}
+	if err := logOutput.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to close log file: %v\n", err)
+	}
+	logOutput = nil
+}
+
+// configPath returns the config file sova reads and sova config set
+// writes.
+func configPath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	return config.DefaultPath()
 }
 
 func PrintSuccess(format string, a ...interface{}) {
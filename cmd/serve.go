@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/go-sova/sova-cli/internal/serve"
+	"github.com/spf13/cobra"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run an HTTP service that generates projects on request",
+	Long: `Run an HTTP service that generates projects on request.
+
+Endpoints:
+  GET  /healthz                         liveness check
+  GET  /v1/templates                    list templates and the JSON schema of their answers
+  GET  /v1/templates/{name}             describe a single template
+  POST /v1/templates/{name}/generate    generate a project and return it as an archive
+
+The generate endpoint accepts a JSON body such as
+  {"project_name": "svc", "format": "zip", "answers": {"UsePostgres": true}}
+and responds with a tar.gz (default) or zip archive of the project.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		addr, _ := cmd.Flags().GetString("addr")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		maxRequest, _ := cmd.Flags().GetInt64("max-request-bytes")
+		maxProject, _ := cmd.Flags().GetInt64("max-project-bytes")
+
+		server := &http.Server{
+			Addr: addr,
+			Handler: serve.NewHandler(serve.Options{
+				MaxRequestBytes: maxRequest,
+				MaxProjectBytes: maxProject,
+				Timeout:         timeout,
+			}),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		errCh := make(chan error, 1)
+		go func() {
+			errCh <- server.ListenAndServe()
+		}()
+
+		PrintInfo("Sova is serving projects on %s", addr)
+
+		select {
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+		}
+
+		PrintInfo("Shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("addr", ":8080", "address to listen on")
+	serveCmd.Flags().Duration("timeout", serve.DefaultTimeout, "maximum time spent on a single request")
+	serveCmd.Flags().Int64("max-request-bytes", serve.DefaultMaxRequestBytes, "maximum size of a request body")
+	serveCmd.Flags().Int64("max-project-bytes", serve.DefaultMaxProjectBytes, "maximum total size of a generated project")
+	rootCmd.AddCommand(serveCmd)
+}
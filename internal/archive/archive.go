@@ -0,0 +1,164 @@
+// Package archive packs generated projects into zip and tar.gz archives.
+// Archives are deterministic: entries are sorted and carry a fixed
+// modification time, so the same project always yields the same bytes.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	TarGz Format = "tar.gz"
+	Zip   Format = "zip"
+)
+
+// ModTime is recorded for every entry. It is the earliest time the zip
+// format can represent.
+var ModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ParseFormat returns the format with the given name.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "tar.gz", "tgz":
+		return TarGz, nil
+	case "zip":
+		return Zip, nil
+	}
+	return "", fmt.Errorf("unsupported archive format: %s (use tar.gz or zip)", name)
+}
+
+// FormatFromPath infers the format from an archive file name.
+func FormatFromPath(name string) (Format, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return TarGz, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return Zip, nil
+	}
+	return "", fmt.Errorf("cannot infer archive format from %s (use a .tar.gz or .zip extension)", name)
+}
+
+// ContentType returns the MIME type of archives in this format.
+func (f Format) ContentType() string {
+	if f == Zip {
+		return "application/zip"
+	}
+	return "application/gzip"
+}
+
+// Write packs every file and directory of fsys into w. Entries are placed
+// below a top-level folder named root.
+func Write(w io.Writer, format Format, fsys fs.FS, root string) error {
+	switch format {
+	case TarGz:
+		return writeTarGz(w, fsys, root)
+	case Zip:
+		return writeZip(w, fsys, root)
+	}
+	return fmt.Errorf("unsupported archive format: %s", format)
+}
+
+// walk calls fn for every entry of fsys in lexical order, passing the
+// entry's name inside fsys and its name inside the archive.
+func walk(fsys fs.FS, root string, fn func(name, entry string, info fs.FileInfo) error) error {
+	return fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(name, path.Join(root, name), info)
+	})
+}
+
+func writeTarGz(w io.Writer, fsys fs.FS, root string) error {
+	gz, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(gz)
+
+	err = walk(fsys, root, func(name, entry string, info fs.FileInfo) error {
+		header := &tar.Header{
+			Name:    entry,
+			Mode:    int64(info.Mode().Perm()),
+			ModTime: ModTime,
+		}
+
+		if info.IsDir() {
+			header.Typeflag = tar.TypeDir
+			header.Name += "/"
+			return tw.WriteHeader(header)
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		header.Typeflag = tar.TypeReg
+		header.Size = int64(len(data))
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write tar archive: %w", err)
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func writeZip(w io.Writer, fsys fs.FS, root string) error {
+	zw := zip.NewWriter(w)
+
+	err := walk(fsys, root, func(name, entry string, info fs.FileInfo) error {
+		header := &zip.FileHeader{
+			Name:     entry,
+			Modified: ModTime,
+		}
+		header.SetMode(info.Mode())
+
+		if info.IsDir() {
+			header.Name += "/"
+			_, err := zw.CreateHeader(header)
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+
+		header.Method = zip.Deflate
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write zip archive: %w", err)
+	}
+
+	return zw.Close()
+}
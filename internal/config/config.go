@@ -0,0 +1,249 @@
+// Package config resolves the global sova configuration. Values come from
+// built-in defaults, overridden by ~/.sova.yaml, overridden by SOVA_*
+// environment variables.
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-sova/sova-cli/internal/goversion"
+	"github.com/spf13/viper"
+)
+
+// Config is the resolved configuration used by every generator.
+type Config struct {
+	Defaults  Defaults
+	Templates Templates
+	Project   Project
+	// Dependencies maps module paths to the versions generated go.mod
+	// files require instead of the template catalog's.
+	Dependencies map[string]string
+	// File is the config file settings and presets are read from and
+	// written to. It need not exist.
+	File string
+}
+
+type Defaults struct {
+	// Template is the project type preselected by sova init.
+	Template string
+	License  string
+	// GoVersion is the Go release new projects target. Empty selects the
+	// local toolchain's version.
+	GoVersion string
+	Author    string
+}
+
+type Templates struct {
+	// Directory holds template files that override the built-in ones.
+	Directory string
+	// Default is the older spelling of Defaults.Template, used when the
+	// latter is not configured.
+	Default string
+}
+
+type Project struct {
+	Structure Structure
+}
+
+// Structure selects the optional top-level directories of new projects.
+type Structure struct {
+	EnableTests   bool
+	EnableDocs    bool
+	EnableScripts bool
+}
+
+// Key is a configuration setting as written in .sova.yaml.
+type Key struct {
+	Name        string
+	Env         string
+	Default     interface{}
+	Description string
+}
+
+// Keys lists every supported setting.
+var Keys = []Key{
+	{Name: "defaults.template", Env: "SOVA_DEFAULT_TEMPLATE", Default: "api", Description: "project type preselected by sova init"},
+	{Name: "defaults.license", Env: "SOVA_DEFAULT_LICENSE", Default: "MIT", Description: "license of new projects"},
+	{Name: "defaults.goVersion", Env: "SOVA_DEFAULT_GO_VERSION", Default: "", Description: "Go version of new projects (empty: the local toolchain's)"},
+	{Name: "defaults.author", Env: "SOVA_DEFAULT_AUTHOR", Default: "", Description: "author of new projects"},
+	{Name: "templates.directory", Env: "SOVA_TEMPLATE_DIR", Default: "~/.sova/templates", Description: "directory whose templates override the built-in ones"},
+	{Name: "templates.default", Env: "SOVA_TEMPLATE_DEFAULT", Default: "", Description: "deprecated alias of defaults.template"},
+	{Name: "project.structure.enableTests", Env: "SOVA_ENABLE_TESTS", Default: true, Description: "create a test/ directory"},
+	{Name: "project.structure.enableDocs", Env: "SOVA_ENABLE_DOCS", Default: true, Description: "create a docs/ directory"},
+	{Name: "project.structure.enableScripts", Env: "SOVA_ENABLE_SCRIPTS", Default: true, Description: "create a scripts/ directory"},
+}
+
+// LookupKey returns the setting called name. Names are case-insensitive.
+func LookupKey(name string) (Key, error) {
+	for _, key := range Keys {
+		if strings.EqualFold(key.Name, name) {
+			return key, nil
+		}
+	}
+	return Key{}, fmt.Errorf("unknown configuration key: %s", name)
+}
+
+// Parse converts value to the type of the setting and validates it.
+func (k Key) Parse(value string) (interface{}, error) {
+	if _, isBool := k.Default.(bool); isBool {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", k.Name)
+		}
+		return b, nil
+	}
+
+	if k.Name == "defaults.goVersion" && value != "" {
+		if _, err := goversion.Parse(value); err != nil {
+			return nil, fmt.Errorf("%s must look like 1.22 or 1.22.3", k.Name)
+		}
+	}
+	return value, nil
+}
+
+// Default returns the built-in configuration, ignoring the config file
+// and the environment.
+func Default() *Config {
+	return &Config{
+		Defaults: Defaults{
+			Template: "api",
+			License:  "MIT",
+		},
+		Templates: Templates{
+			Directory: expandHome("~/.sova/templates"),
+		},
+		Project: Project{
+			Structure: Structure{
+				EnableTests:   true,
+				EnableDocs:    true,
+				EnableScripts: true,
+			},
+		},
+	}
+}
+
+// Bind registers the defaults and environment variables of every setting
+// with v.
+func Bind(v *viper.Viper) {
+	for _, key := range Keys {
+		// The template default is resolved from both of its keys, so
+		// neither gets a viper default that would hide the other.
+		if key.Name != "defaults.template" && key.Name != "templates.default" {
+			v.SetDefault(key.Name, key.Default)
+		}
+		v.BindEnv(key.Name, key.Env)
+	}
+}
+
+// Load resolves the configuration from v after binding the settings. It
+// returns a usable configuration even when it also returns an error.
+func Load(v *viper.Viper) (*Config, error) {
+	Bind(v)
+
+	cfg := &Config{
+		Defaults: Defaults{
+			Template:  v.GetString("defaults.template"),
+			License:   v.GetString("defaults.license"),
+			GoVersion: v.GetString("defaults.goVersion"),
+			Author:    v.GetString("defaults.author"),
+		},
+		Templates: Templates{
+			Directory: expandHome(v.GetString("templates.directory")),
+			Default:   v.GetString("templates.default"),
+		},
+		Project: Project{
+			Structure: Structure{
+				EnableTests:   v.GetBool("project.structure.enableTests"),
+				EnableDocs:    v.GetBool("project.structure.enableDocs"),
+				EnableScripts: v.GetBool("project.structure.enableScripts"),
+			},
+		},
+	}
+
+	if cfg.Defaults.Template == "" {
+		cfg.Defaults.Template = cfg.Templates.Default
+	}
+	if cfg.Defaults.Template == "" {
+		cfg.Defaults.Template = "api"
+	}
+
+	// An invalid version falls back to the default so the rest of the
+	// configuration stays usable.
+	if cfg.Defaults.GoVersion != "" {
+		if _, err := goversion.Parse(cfg.Defaults.GoVersion); err != nil {
+			cfg.Defaults.GoVersion = Default().Defaults.GoVersion
+			return cfg, fmt.Errorf("defaults.goVersion must look like 1.22 or 1.22.3: %v", err)
+		}
+	}
+
+	return cfg, nil
+}
+
+// Get returns the resolved value of the setting called name.
+func (c *Config) Get(name string) (interface{}, error) {
+	key, err := LookupKey(name)
+	if err != nil {
+		return nil, err
+	}
+
+	switch key.Name {
+	case "defaults.template":
+		return c.Defaults.Template, nil
+	case "defaults.license":
+		return c.Defaults.License, nil
+	case "defaults.goVersion":
+		return c.Defaults.GoVersion, nil
+	case "defaults.author":
+		return c.Defaults.Author, nil
+	case "templates.directory":
+		return c.Templates.Directory, nil
+	case "templates.default":
+		return c.Templates.Default, nil
+	case "project.structure.enableTests":
+		return c.Project.Structure.EnableTests, nil
+	case "project.structure.enableDocs":
+		return c.Project.Structure.EnableDocs, nil
+	case "project.structure.enableScripts":
+		return c.Project.Structure.EnableScripts, nil
+	}
+	return nil, fmt.Errorf("unknown configuration key: %s", name)
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[1:])
+		}
+	}
+	return path
+}
+
+var (
+	mu      sync.RWMutex
+	current *Config
+)
+
+// Current returns the configuration resolved at startup, or the defaults
+// when none was set.
+func Current() *Config {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	if current == nil {
+		return Default()
+	}
+	return current
+}
+
+// SetCurrent makes cfg the configuration returned by Current.
+func SetCurrent(cfg *Config) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	current = cfg
+}
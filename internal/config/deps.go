@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadDependencies reads the module versions stored under dependencies in
+// the config file at path, for example:
+//
+//	dependencies:
+//	  github.com/gin-gonic/gin: v1.10.0
+//
+// They override the versions of the template dependency catalogs. Module
+// paths contain dots, so they are read here rather than through viper.
+func LoadDependencies(path string) (map[string]string, error) {
+	doc, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := mappingPath(doc.Content[0], []string{"dependencies"}, false)
+	if err != nil || node == nil {
+		return nil, err
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("dependencies in %s is not a mapping", path)
+	}
+
+	versions := make(map[string]string, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i+1].Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("dependency %s in %s is not a version", node.Content[i].Value, path)
+		}
+		versions[node.Content[i].Value] = node.Content[i+1].Value
+	}
+	return versions, nil
+}
+
+// SetDependency stores the version of module in the config file at path.
+func SetDependency(path, module, version string) error {
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	node, err := mappingPath(doc.Content[0], []string{"dependencies"}, true)
+	if err != nil || node.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot set dependency %s: dependencies in %s is not a mapping", module, path)
+	}
+
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: version}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == module {
+			value.LineComment = node.Content[i+1].LineComment
+			node.Content[i+1] = value
+			return writeDocument(path, doc)
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: module}, value)
+	return writeDocument(path, doc)
+}
+
+// UnsetDependency removes the version of module from the config file at
+// path.
+func UnsetDependency(path, module string) error {
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	node, err := mappingPath(doc.Content[0], []string{"dependencies"}, false)
+	if err != nil {
+		return err
+	}
+	if node != nil && node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == module {
+				node.Content = append(node.Content[:i], node.Content[i+2:]...)
+				return writeDocument(path, doc)
+			}
+		}
+	}
+
+	return fmt.Errorf("no version of %s is configured", module)
+}
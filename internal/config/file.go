@@ -0,0 +1,126 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath returns the config file used when neither --config nor
+// SOVA_CONFIG is set.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".sova.yaml"), nil
+}
+
+// SetInFile stores value for the setting called name in the YAML file at
+// path, creating the file if needed. Comments and unrelated settings in
+// the file are preserved.
+func SetInFile(path, name, value string) error {
+	key, err := LookupKey(name)
+	if err != nil {
+		return err
+	}
+	parsed, err := key.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	node, err := mappingPath(doc.Content[0], strings.Split(key.Name, "."), true)
+	if err != nil {
+		return fmt.Errorf("cannot set %s in %s: %v", key.Name, path, err)
+	}
+
+	*node = yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value, LineComment: node.LineComment}
+	if b, ok := parsed.(bool); ok {
+		node.Tag = "!!bool"
+		node.Value = strconv.FormatBool(b)
+	}
+
+	return writeDocument(path, doc)
+}
+
+// readDocument parses the YAML file at path. A missing or empty file
+// yields a document holding an empty mapping.
+func readDocument(path string) (*yaml.Node, error) {
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+		}
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("config file %s is not a mapping", path)
+	}
+	return &doc, nil
+}
+
+func writeDocument(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode config file: %v", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return fmt.Errorf("failed to encode config file: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// mappingPath walks the nested mappings named by keys and returns the
+// last value. Missing mappings are added when create is set, otherwise
+// nil is returned.
+func mappingPath(node *yaml.Node, keys []string, create bool) (*yaml.Node, error) {
+	for i, key := range keys {
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s is not a mapping", strings.Join(keys[:i], "."))
+		}
+
+		child := mappingValue(node, key)
+		if child == nil {
+			if !create {
+				return nil, nil
+			}
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, child)
+		}
+		node = child
+	}
+	return node, nil
+}
+
+// mappingValue returns the value stored under name in a mapping node,
+// matching keys case-insensitively like viper does.
+func mappingValue(node *yaml.Node, name string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, name) {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
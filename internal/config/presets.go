@@ -0,0 +1,140 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Preset is a named set of answers stored under presets in the config
+// file, for example:
+//
+//	presets:
+//	  team-api:
+//	    template: api
+//	    templateVersion: 1.0.0
+//	    answers:
+//	      UsePostgres: true
+//	      UseRedis: true
+type Preset struct {
+	Name     string `yaml:"-"`
+	Template string `yaml:"template"`
+	// TemplateVersion pins the preset to a template version. Empty means
+	// any version.
+	TemplateVersion string                 `yaml:"templateVersion,omitempty"`
+	Description     string                 `yaml:"description,omitempty"`
+	Answers         map[string]interface{} `yaml:"answers,omitempty"`
+}
+
+var presetNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// LoadPresets reads the presets stored in the config file at path, sorted
+// by name. A missing file has no presets.
+func LoadPresets(path string) ([]Preset, error) {
+	doc, err := readDocument(path)
+	if err != nil {
+		return nil, err
+	}
+
+	node, err := mappingPath(doc.Content[0], []string{"presets"}, false)
+	if err != nil || node == nil {
+		return nil, err
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("presets in %s is not a mapping", path)
+	}
+
+	presets := make([]Preset, 0, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var preset Preset
+		if err := node.Content[i+1].Decode(&preset); err != nil {
+			return nil, fmt.Errorf("invalid preset %s in %s: %v", node.Content[i].Value, path, err)
+		}
+		preset.Name = node.Content[i].Value
+		presets = append(presets, preset)
+	}
+
+	sort.Slice(presets, func(i, j int) bool { return presets[i].Name < presets[j].Name })
+	return presets, nil
+}
+
+// FindPreset returns the preset called name from the config file at path.
+func FindPreset(path, name string) (*Preset, error) {
+	presets, err := LoadPresets(path)
+	if err != nil {
+		return nil, err
+	}
+	for i := range presets {
+		if presets[i].Name == name {
+			return &presets[i], nil
+		}
+	}
+	return nil, fmt.Errorf("unknown preset: %s (see sova presets list)", name)
+}
+
+// SavePreset adds or replaces a preset in the config file at path.
+func SavePreset(path string, preset Preset) error {
+	if !presetNamePattern.MatchString(preset.Name) {
+		return fmt.Errorf("invalid preset name %q: use letters, digits, '.', '_' or '-'", preset.Name)
+	}
+	if preset.Template == "" {
+		return fmt.Errorf("preset %s needs a template", preset.Name)
+	}
+
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	presets, err := mappingPath(doc.Content[0], []string{"presets"}, true)
+	if err != nil || presets.Kind != yaml.MappingNode {
+		return fmt.Errorf("cannot save preset %s: presets in %s is not a mapping", preset.Name, path)
+	}
+
+	node := presetNode(presets, preset.Name)
+	if node == nil {
+		node = &yaml.Node{}
+		presets.Content = append(presets.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: preset.Name}, node)
+	}
+	if err := node.Encode(preset); err != nil {
+		return fmt.Errorf("failed to encode preset %s: %v", preset.Name, err)
+	}
+
+	return writeDocument(path, doc)
+}
+
+// DeletePreset removes a preset from the config file at path.
+func DeletePreset(path, name string) error {
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	node, err := mappingPath(doc.Content[0], []string{"presets"}, false)
+	if err != nil {
+		return err
+	}
+	if node != nil && node.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == name {
+				node.Content = append(node.Content[:i], node.Content[i+2:]...)
+				return writeDocument(path, doc)
+			}
+		}
+	}
+
+	return fmt.Errorf("unknown preset: %s", name)
+}
+
+// presetNode returns the value of the preset called name. Unlike
+// settings, preset names are case-sensitive.
+func presetNode(presets *yaml.Node, name string) *yaml.Node {
+	for i := 0; i+1 < len(presets.Content); i += 2 {
+		if presets.Content[i].Value == name {
+			return presets.Content[i+1]
+		}
+	}
+	return nil
+}
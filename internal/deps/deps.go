@@ -0,0 +1,174 @@
+// Package deps reads the dependency catalogs of templates and resolves the
+// module versions generated go.mod files require.
+package deps
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CatalogFile is the file, relative to a template directory, that lists
+// the modules the template's projects may require.
+const CatalogFile = "deps.yaml"
+
+// Dependency is a module a template may require.
+type Dependency struct {
+	Module  string `yaml:"module"`
+	Version string `yaml:"version"`
+	// Constraint limits the versions users may override Version with,
+	// e.g. ">= v1.9.0, < v2.0.0". Empty allows any version.
+	Constraint string `yaml:"constraint"`
+	// When is a text/template condition evaluated against the project
+	// answers, such as ".UseZap". The module is only required when it is
+	// true.
+	When string `yaml:"when"`
+
+	constraint Constraint
+	when       *template.Template
+}
+
+// Catalog is the list of modules of a template.
+type Catalog struct {
+	Dependencies []Dependency `yaml:"dependencies"`
+}
+
+// Requirement is a module version a generated go.mod requires.
+type Requirement struct {
+	Module  string
+	Version string
+	// Overridden is set when the version comes from the configuration
+	// rather than the catalog.
+	Overridden bool
+}
+
+// ParseCatalog parses and validates a dependency catalog.
+func ParseCatalog(data []byte) (*Catalog, error) {
+	var catalog Catalog
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&catalog); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse dependency catalog: %v", err)
+	}
+
+	seen := make(map[string]bool, len(catalog.Dependencies))
+	for i := range catalog.Dependencies {
+		d := &catalog.Dependencies[i]
+		if err := d.compile(); err != nil {
+			return nil, fmt.Errorf("dependency %q: %v", d.Module, err)
+		}
+		if seen[d.Module] {
+			return nil, fmt.Errorf("dependency %q is declared twice", d.Module)
+		}
+		seen[d.Module] = true
+	}
+
+	return &catalog, nil
+}
+
+// LoadCatalog reads the dependency catalog of the template directory dir
+// in fsys. A directory without a catalog requires no modules.
+func LoadCatalog(fsys fs.FS, dir string) (*Catalog, error) {
+	data, err := fs.ReadFile(fsys, path.Join(dir, CatalogFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Catalog{}, nil
+		}
+		return nil, err
+	}
+
+	catalog, err := ParseCatalog(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path.Join(dir, CatalogFile), err)
+	}
+	return catalog, nil
+}
+
+func (d *Dependency) compile() error {
+	if d.Module == "" {
+		return fmt.Errorf("module is required")
+	}
+	if _, err := ParseVersion(d.Version); err != nil {
+		return err
+	}
+
+	constraint, err := ParseConstraint(d.Constraint)
+	if err != nil {
+		return err
+	}
+	d.constraint = constraint
+	if !constraint.Allows(d.Version) {
+		return fmt.Errorf("version %s does not satisfy %s", d.Version, d.Constraint)
+	}
+
+	if d.When != "" {
+		when, err := template.New(d.Module).Option("missingkey=zero").Parse("{{if " + d.When + "}}true{{end}}")
+		if err != nil {
+			return fmt.Errorf("invalid when condition: %v", err)
+		}
+		d.when = when
+	}
+	return nil
+}
+
+// Enabled reports whether a project with answers requires the module.
+func (d *Dependency) Enabled(answers map[string]interface{}) (bool, error) {
+	if d.when == nil {
+		return true, nil
+	}
+
+	var buf bytes.Buffer
+	if err := d.when.Execute(&buf, answers); err != nil {
+		return false, fmt.Errorf("failed to evaluate when condition of %s: %v", d.Module, err)
+	}
+	return buf.String() == "true", nil
+}
+
+// Resolve returns the version of the module, taken from overrides when it
+// has one. An override must satisfy the dependency's constraint.
+func (d *Dependency) Resolve(overrides map[string]string) (Requirement, error) {
+	version, ok := overrides[d.Module]
+	if !ok {
+		return Requirement{Module: d.Module, Version: d.Version}, nil
+	}
+
+	if _, err := ParseVersion(version); err != nil {
+		return Requirement{}, fmt.Errorf("dependency %s: %v", d.Module, err)
+	}
+	if !d.constraint.Allows(version) {
+		return Requirement{}, fmt.Errorf("dependency %s: configured version %s does not satisfy %s", d.Module, version, d.Constraint)
+	}
+	return Requirement{Module: d.Module, Version: version, Overridden: version != d.Version}, nil
+}
+
+// Requirements returns the modules a project with answers requires,
+// sorted by module path.
+func (c *Catalog) Requirements(answers map[string]interface{}, overrides map[string]string) ([]Requirement, error) {
+	var requirements []Requirement
+	for i := range c.Dependencies {
+		d := &c.Dependencies[i]
+		enabled, err := d.Enabled(answers)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+
+		requirement, err := d.Resolve(overrides)
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, requirement)
+	}
+
+	sort.Slice(requirements, func(i, j int) bool { return requirements[i].Module < requirements[j].Module })
+	return requirements, nil
+}
@@ -0,0 +1,132 @@
+package deps
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic module version such as v1.9.1 or v2.0.0-rc.1.
+type Version struct {
+	Major, Minor, Patch int
+	Prerelease          string
+}
+
+var versionPattern = regexp.MustCompile(`^v(\d+)\.(\d+)\.(\d+)(?:-([0-9A-Za-z.-]+))?(?:\+[0-9A-Za-z.-]+)?$`)
+
+// ParseVersion parses a semantic version with the leading "v" Go modules
+// use.
+func ParseVersion(s string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid module version: %q", s)
+	}
+
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	v.Patch, _ = strconv.Atoi(m[3])
+	v.Prerelease = m[4]
+	return v, nil
+}
+
+// Compare returns -1, 0 or 1 as v is older than, equal to or newer than o.
+// Prereleases are older than their release and compare as strings.
+func (v Version) Compare(o Version) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == o.Prerelease:
+		return 0
+	case v.Prerelease == "":
+		return 1
+	case o.Prerelease == "":
+		return -1
+	case v.Prerelease < o.Prerelease:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// Constraint is a list of comparisons a version must all satisfy, written
+// like ">= v1.9.0, < v2.0.0".
+type Constraint []comparison
+
+type comparison struct {
+	op      string
+	version Version
+}
+
+// ParseConstraint parses a comma-separated list of comparisons using the
+// operators =, !=, <, <=, > and >=. An empty string allows any version.
+func ParseConstraint(s string) (Constraint, error) {
+	var c Constraint
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		i := strings.IndexByte(part, 'v')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid constraint %q: %q names no version", s, part)
+		}
+		op := strings.TrimSpace(part[:i])
+		switch op {
+		case "", "=", "!=", "<", "<=", ">", ">=":
+		default:
+			return nil, fmt.Errorf("invalid constraint %q: unknown operator %q", s, op)
+		}
+
+		version, err := ParseVersion(part[i:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid constraint %q: %v", s, err)
+		}
+		if op == "" {
+			op = "="
+		}
+		c = append(c, comparison{op: op, version: version})
+	}
+	return c, nil
+}
+
+// Allows reports whether version satisfies every comparison. Invalid
+// versions are never allowed.
+func (c Constraint) Allows(version string) bool {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false
+	}
+
+	for _, cmp := range c {
+		r := v.Compare(cmp.version)
+		var ok bool
+		switch cmp.op {
+		case "=":
+			ok = r == 0
+		case "!=":
+			ok = r != 0
+		case "<":
+			ok = r < 0
+		case "<=":
+			ok = r <= 0
+		case ">":
+			ok = r > 0
+		case ">=":
+			ok = r >= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,445 @@
+// Package doctor compares a generated project with what its template
+// renders for the answers recorded in the project manifest.
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/deps"
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/goversion"
+	"github.com/go-sova/sova-cli/internal/openapi"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	Pass Status = iota
+	// Warn marks a difference the project may have on purpose.
+	Warn
+	// Fail marks a problem that keeps the project from building or
+	// running as generated.
+	Fail
+)
+
+// Result is the outcome of one check. Fix tells the user how to resolve a
+// warning or failure.
+type Result struct {
+	Check   string
+	Status  Status
+	Message string
+	Fix     string
+}
+
+// Report lists the results of every check of a project.
+type Report struct {
+	Manifest *project.Manifest
+	Results  []Result
+}
+
+// Failures returns the number of failed checks.
+func (r *Report) Failures() int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Status == Fail {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *Report) add(check string, status Status, message, fix string) {
+	r.Results = append(r.Results, Result{Check: check, Status: status, Message: message, Fix: fix})
+}
+
+// Run checks the project at the root of dir. The expected project is
+// rendered with cfg, so overrides of templates and dependency versions
+// apply.
+func Run(dir fs.FS, cfg *config.Config) (*Report, error) {
+	manifest, answers, err := project.ReadManifest(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no %s found: run sova doctor in the root of a project sova generated", project.ManifestFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	gen, err := project.NewGenerator(manifest.Template, manifest.ProjectName, manifest.ProjectName, answers)
+	if err != nil {
+		return nil, err
+	}
+	expected := vfs.NewMemFS()
+	gen.SetConfig(cfg)
+	gen.SetFS(expected)
+	gen.SetEvents(events.Discard)
+
+	var spec *openapi.Spec
+	if manifest.OpenAPI != "" {
+		data, err := fs.ReadFile(dir, manifest.OpenAPI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the OpenAPI spec %s recorded in %s: %v", manifest.OpenAPI, project.ManifestFile, err)
+		}
+		if spec, err = openapi.Load(data); err != nil {
+			return nil, fmt.Errorf("%s: %v", manifest.OpenAPI, err)
+		}
+		if g, ok := gen.(openAPIGenerator); ok {
+			g.SetOpenAPI(spec)
+		}
+	}
+
+	if err := project.Run(gen); err != nil {
+		return nil, fmt.Errorf("failed to render the %s template: %v", manifest.Template, err)
+	}
+
+	r := &Report{Manifest: manifest}
+	if err := r.checkStructure(dir, expected, manifest, spec); err != nil {
+		return nil, err
+	}
+	r.checkGoMod(dir, expected, manifest)
+	r.checkEnv(dir, expected)
+	r.checkCompose(dir, expected)
+	r.checkToolchain(dir, manifest)
+	return r, nil
+}
+
+// openAPIGenerator is implemented by generators of projects whose API can
+// be generated from an OpenAPI spec.
+type openAPIGenerator interface {
+	SetOpenAPI(spec *openapi.Spec)
+}
+
+// ownChecks are files compared in detail by a check of their own.
+var ownChecks = map[string]bool{"go.mod": true, ".env": true, "docker-compose.yml": true, project.ManifestFile: true}
+
+// checkStructure compares the files of the project with those of the
+// expected project. Directories are only expected when they hold files:
+// version control does not keep empty ones. Handler stubs of the OpenAPI
+// spec are the user's to rename, so only operations API lacks are reported.
+func (r *Report) checkStructure(dir, expected fs.FS, manifest *project.Manifest, spec *openapi.Spec) error {
+	stubs := map[string]bool{}
+	if spec != nil {
+		planned, _, err := openapi.Stubs(vfs.NewMemFS(), spec, manifest.ProjectName)
+		if err != nil {
+			return err
+		}
+		for path := range planned {
+			stubs[path] = true
+		}
+	}
+
+	var paths []string
+	dirs := map[string]bool{}
+	err := fs.WalkDir(expected, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || ownChecks[name] || stubs[name] {
+			return err
+		}
+		paths = append(paths, name)
+		for parent := path.Dir(name); parent != "."; parent = path.Dir(parent) {
+			dirs[parent] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	missing := 0
+	for _, d := range sortedNames(dirs) {
+		if !vfs.IsDir(dir, d) {
+			missing++
+			r.add("structure", Fail, fmt.Sprintf("directory %s is missing", d), fmt.Sprintf("mkdir -p %s", d))
+		}
+	}
+
+	for _, name := range paths {
+		if !vfs.Exists(dir, name) {
+			missing++
+			r.add("structure", Fail, fmt.Sprintf("%s is missing", name),
+				fmt.Sprintf("restore it from version control, or copy it from a project generated with 'sova init %s' and the answers in %s", manifest.Template, project.ManifestFile))
+		}
+	}
+
+	if spec != nil {
+		lacking, _, err := openapi.Stubs(dir, spec, manifest.ProjectName)
+		if err != nil {
+			return err
+		}
+		if len(lacking) > 0 {
+			missing++
+			r.add("structure", Fail, fmt.Sprintf("%s does not implement every operation of %s", openapi.HandlersDir, manifest.OpenAPI), "sova add openapi")
+		}
+	}
+
+	if missing == 0 {
+		r.add("structure", Pass, fmt.Sprintf("all %d directories and %d files of the %s template exist", len(dirs), len(paths), manifest.Template), "")
+	}
+	return nil
+}
+
+func (r *Report) checkGoMod(dir, expected fs.FS, manifest *project.Manifest) {
+	data, err := fs.ReadFile(dir, "go.mod")
+	if err != nil {
+		r.add("go.mod", Fail, "go.mod is missing", fmt.Sprintf("go mod init %s", manifest.ProjectName))
+		return
+	}
+	got := parseGoMod(data)
+
+	data, err = fs.ReadFile(expected, "go.mod")
+	if err != nil {
+		return
+	}
+	want := parseGoMod(data)
+
+	problems := 0
+	if info, err := templates.LoadInfo(manifest.Template); err == nil && info.MinGoVersion != "" {
+		min, _ := goversion.Parse(info.MinGoVersion)
+		if v, err := goversion.Parse(got.goVersion); err != nil || v.Less(min) {
+			problems++
+			r.add("go.mod", Fail, fmt.Sprintf("go.mod declares go %s, but the %s template needs Go %s", orNone(got.goVersion), manifest.Template, min.Language()),
+				fmt.Sprintf("go mod edit -go=%s", min.Directive()))
+		}
+	}
+
+	modules := make([]string, 0, len(want.requires))
+	for module := range want.requires {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		wantVersion := want.requires[module]
+		gotVersion, ok := got.requires[module]
+		if !ok {
+			problems++
+			r.add("go.mod", Fail, fmt.Sprintf("go.mod does not require %s", module), fmt.Sprintf("go get %s@%s", module, wantVersion))
+			continue
+		}
+
+		wv, err1 := deps.ParseVersion(wantVersion)
+		gv, err2 := deps.ParseVersion(gotVersion)
+		if err1 == nil && err2 == nil && gv.Compare(wv) < 0 {
+			problems++
+			r.add("go.mod", Warn, fmt.Sprintf("go.mod requires %s %s, older than the %s the template is written for", module, gotVersion, wantVersion),
+				fmt.Sprintf("go get %s@%s", module, wantVersion))
+		}
+	}
+
+	if problems == 0 {
+		r.add("go.mod", Pass, fmt.Sprintf("go.mod requires the %d modules the project uses", len(modules)), "")
+	}
+}
+
+func (r *Report) checkEnv(dir, expected fs.FS) {
+	data, err := fs.ReadFile(expected, ".env")
+	if err != nil {
+		return
+	}
+	want, order := parseEnv(data)
+	if len(order) == 0 {
+		return
+	}
+
+	data, err = fs.ReadFile(dir, ".env")
+	if err != nil {
+		lines := make([]string, len(order))
+		for i, key := range order {
+			lines[i] = key + "=" + want[key]
+		}
+		r.add(".env", Fail, ".env is missing", "create .env with "+strings.Join(lines, ", "))
+		return
+	}
+	got, _ := parseEnv(data)
+
+	missing := 0
+	for _, key := range order {
+		if _, ok := got[key]; !ok {
+			missing++
+			r.add(".env", Fail, fmt.Sprintf(".env does not set %s", key), fmt.Sprintf("add %s=%s to .env", key, want[key]))
+		}
+	}
+	if missing == 0 {
+		r.add(".env", Pass, fmt.Sprintf(".env sets the %d variables the project reads", len(order)), "")
+	}
+}
+
+func (r *Report) checkCompose(dir, expected fs.FS) {
+	data, err := fs.ReadFile(expected, "docker-compose.yml")
+	if err != nil {
+		return
+	}
+	want, err := parseCompose(data)
+	if err != nil {
+		return
+	}
+
+	data, err = fs.ReadFile(dir, "docker-compose.yml")
+	if err != nil {
+		if len(want) > 0 {
+			r.add("docker-compose", Fail, "docker-compose.yml is missing", "restore it from version control")
+		}
+		return
+	}
+	got, err := parseCompose(data)
+	if err != nil {
+		r.add("docker-compose", Fail, fmt.Sprintf("docker-compose.yml is invalid: %v", err), "fix the YAML syntax of docker-compose.yml")
+		return
+	}
+
+	problems := 0
+	for _, name := range sortedKeys(want) {
+		if _, ok := got[name]; !ok {
+			problems++
+			fix := fmt.Sprintf("add the %s service to docker-compose.yml", name)
+			if image := want[name].Image; image != "" {
+				fix = fmt.Sprintf("add the %s service (image %s) to docker-compose.yml", name, image)
+			}
+			r.add("docker-compose", Fail, fmt.Sprintf("docker-compose.yml has no %s service, which an enabled integration needs", name), fix)
+		}
+	}
+	for _, name := range sortedKeys(got) {
+		if _, ok := want[name]; !ok {
+			problems++
+			r.add("docker-compose", Warn, fmt.Sprintf("docker-compose.yml runs %s, which no enabled integration uses", name),
+				fmt.Sprintf("remove the %s service, or enable its integration in %s", name, project.ManifestFile))
+		}
+	}
+
+	if problems == 0 {
+		r.add("docker-compose", Pass, fmt.Sprintf("docker-compose.yml runs the %d services of the enabled integrations", len(want)), "")
+	}
+}
+
+func (r *Report) checkToolchain(dir fs.FS, manifest *project.Manifest) {
+	local, err := goversion.Local()
+	if err != nil {
+		r.add("toolchain", Fail, err.Error(), "install Go from https://go.dev/dl/ and make sure it is on PATH")
+		return
+	}
+
+	need := goversion.Version{}
+	source := ""
+	if info, err := templates.LoadInfo(manifest.Template); err == nil && info.MinGoVersion != "" {
+		if v, err := goversion.Parse(info.MinGoVersion); err == nil {
+			need, source = v, "the "+manifest.Template+" template"
+		}
+	}
+	if data, err := fs.ReadFile(dir, "go.mod"); err == nil {
+		if v, err := goversion.Parse(parseGoMod(data).goVersion); err == nil && need.Less(v) {
+			need, source = v, "go.mod"
+		}
+	}
+
+	if local.Less(need) {
+		r.add("toolchain", Fail, fmt.Sprintf("Go %s is installed, but %s needs Go %s", local, source, need),
+			fmt.Sprintf("install Go %s or newer from https://go.dev/dl/", need.Language()))
+		return
+	}
+	r.add("toolchain", Pass, fmt.Sprintf("Go %s can build the project", local), "")
+}
+
+type goMod struct {
+	goVersion string
+	requires  map[string]string
+}
+
+// parseGoMod reads the go directive and the requirements of a go.mod
+// file. It accepts the files sova generates and `go mod tidy` writes, not
+// the full grammar.
+func parseGoMod(data []byte) goMod {
+	mod := goMod{requires: map[string]string{}}
+	inRequire := false
+	for _, line := range strings.Split(string(data), "\n") {
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+		case inRequire && fields[0] == ")":
+			inRequire = false
+		case inRequire && len(fields) >= 2:
+			mod.requires[fields[0]] = fields[1]
+		case fields[0] == "go" && len(fields) == 2:
+			mod.goVersion = fields[1]
+		case fields[0] == "require" && len(fields) == 2 && fields[1] == "(":
+			inRequire = true
+		case fields[0] == "require" && len(fields) >= 3:
+			mod.requires[fields[1]] = fields[2]
+		}
+	}
+	return mod
+}
+
+// parseEnv returns the variables a .env file sets, and their names in
+// the order they appear.
+func parseEnv(data []byte) (map[string]string, []string) {
+	vars := map[string]string{}
+	var order []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if _, seen := vars[key]; !seen {
+			order = append(order, key)
+		}
+		vars[key] = strings.TrimSpace(value)
+	}
+	return vars, order
+}
+
+type composeService struct {
+	Image string `yaml:"image"`
+}
+
+func parseCompose(data []byte) (map[string]composeService, error) {
+	var compose struct {
+		Services map[string]composeService `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &compose); err != nil {
+		return nil, err
+	}
+	return compose.Services, nil
+}
+
+func sortedNames(m map[string]bool) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedKeys(m map[string]composeService) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,175 @@
+// Package events describes the progress of a project generation as a
+// stream of events, written as newline-delimited JSON for tools or
+// rendered as text for people.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Type names what happened.
+type Type string
+
+const (
+	// Plan lists the directories and files a generator is about to write.
+	Plan Type = "plan"
+	// DirCreated follows the creation of the directory Path.
+	DirCreated Type = "dir_created"
+	// FileWritten follows the rendering of Template into Path.
+	FileWritten Type = "file_written"
+	// Warning reports a problem that does not stop the generation.
+	Warning Type = "warning"
+	// Error reports the error the generation failed with. It is the last
+	// event of a failed generation.
+	Error Type = "error"
+	// Done is the last event of a successful generation.
+	Done Type = "done"
+)
+
+// Event is one step of a project generation. Only the fields that apply
+// to its Type are set.
+type Event struct {
+	Type    Type      `json:"type"`
+	Time    time.Time `json:"time"`
+	Project string    `json:"project,omitempty"`
+	// Path is the directory or file the event is about, or the
+	// destination of the project for Done.
+	Path     string `json:"path,omitempty"`
+	Template string `json:"template,omitempty"`
+	// DurationMS is how long the step took, in milliseconds.
+	DurationMS float64  `json:"duration_ms,omitempty"`
+	Dirs       []string `json:"dirs,omitempty"`
+	Files      []string `json:"files,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	// Steps and Hints tell the user what to do with a new project.
+	Steps []string `json:"next_steps,omitempty"`
+	Hints []string `json:"hints,omitempty"`
+}
+
+// Milliseconds returns the time elapsed since start in milliseconds, for
+// Event.DurationMS.
+func Milliseconds(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+// Sink receives events. Sinks are safe for concurrent use.
+type Sink interface {
+	Emit(e Event)
+}
+
+// Format selects how events are written.
+type Format string
+
+const (
+	Text Format = "text"
+	JSON Format = "json"
+)
+
+// ParseFormat parses "text" or "json".
+func ParseFormat(s string) (Format, error) {
+	switch Format(strings.ToLower(s)) {
+	case Text, "":
+		return Text, nil
+	case JSON:
+		return JSON, nil
+	default:
+		return Text, fmt.Errorf("invalid output format %q: use text or json", s)
+	}
+}
+
+// Discard drops every event.
+var Discard Sink = discard{}
+
+type discard struct{}
+
+func (discard) Emit(Event) {}
+
+type jsonSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONSink writes each event to w as one JSON object per line.
+func NewJSONSink(w io.Writer) Sink {
+	return &jsonSink{enc: json.NewEncoder(w)}
+}
+
+func (s *jsonSink) Emit(e Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.enc.Encode(e)
+}
+
+type textSink struct {
+	mu     sync.Mutex
+	out    io.Writer
+	errOut io.Writer
+}
+
+// NewTextSink renders events as the human-readable progress output of
+// sova. Warnings go to errOut. Plans and errors are not printed:
+// commands report their errors themselves.
+func NewTextSink(out, errOut io.Writer) Sink {
+	return &textSink{out: out, errOut: errOut}
+}
+
+func (s *textSink) Emit(e Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch e.Type {
+	case DirCreated:
+		fmt.Fprintf(s.out, "Created directory: %s\n", e.Path)
+	case FileWritten:
+		fmt.Fprintf(s.out, "Created file: %s\n", e.Path)
+	case Warning:
+		fmt.Fprintf(s.errOut, "Warning: %s\n", e.Message)
+	case Done:
+		if len(e.Steps) == 0 && len(e.Hints) == 0 {
+			fmt.Fprintln(s.out, e.Message)
+			return
+		}
+		fmt.Fprintf(s.out, "\n%s\n", e.Message)
+		if len(e.Steps) > 0 {
+			fmt.Fprintln(s.out, "\nNext steps:")
+			for _, step := range e.Steps {
+				fmt.Fprintln(s.out, step)
+			}
+		}
+		if len(e.Hints) > 0 {
+			fmt.Fprintln(s.out)
+			for _, hint := range e.Hints {
+				fmt.Fprintln(s.out, hint)
+			}
+		}
+	}
+}
+
+type filterSink struct {
+	sink  Sink
+	types map[Type]bool
+}
+
+// Only passes the events of the given types on to sink.
+func Only(sink Sink, types ...Type) Sink {
+	f := &filterSink{sink: sink, types: make(map[Type]bool, len(types))}
+	for _, t := range types {
+		f.types[t] = true
+	}
+	return f
+}
+
+func (f *filterSink) Emit(e Event) {
+	if f.types[e.Type] {
+		f.sink.Emit(e)
+	}
+}
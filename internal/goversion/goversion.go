@@ -0,0 +1,130 @@
+// Package goversion detects the local Go toolchain and derives the Go
+// versions written into generated projects.
+package goversion
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Version is a Go release such as 1.22.3.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// Fallback is used when no version is requested and the local toolchain
+// cannot be detected.
+var Fallback = Version{Major: 1, Minor: 21}
+
+var versionPattern = regexp.MustCompile(`^(?:go)?(\d+)\.(\d+)(?:\.(\d+))?(?:(?:rc|beta)\d+)?$`)
+
+// Parse parses versions like "1.22", "1.22.3", "go1.22.3" and "go1.23rc1".
+// Release candidates count as the .0 release.
+func Parse(s string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid Go version: %q", s)
+	}
+
+	var v Version
+	v.Major, _ = strconv.Atoi(m[1])
+	v.Minor, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		v.Patch, _ = strconv.Atoi(m[3])
+	}
+	return v, nil
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Language returns the major.minor language version, e.g. "1.22". It is
+// also the tag of the matching golang Docker images.
+func (v Version) Language() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Less reports whether v is older than o.
+func (v Version) Less(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	if v.Minor != o.Minor {
+		return v.Minor < o.Minor
+	}
+	return v.Patch < o.Patch
+}
+
+// Directive returns the version for the go directive of go.mod. From Go
+// 1.21 on it names the .0 release, so any patch release can build the
+// module; older releases only understand major.minor.
+func (v Version) Directive() string {
+	if v.Less(Version{Major: 1, Minor: 21}) {
+		return v.Language()
+	}
+	return Version{Major: v.Major, Minor: v.Minor}.String()
+}
+
+// Toolchain returns the toolchain line of go.mod, e.g. "go1.22.3", or ""
+// when the go directive already names the exact release or the release
+// predates toolchain lines.
+func (v Version) Toolchain() string {
+	if v.Patch == 0 || v.Less(Version{Major: 1, Minor: 21}) {
+		return ""
+	}
+	return "go" + v.String()
+}
+
+var (
+	localOnce    sync.Once
+	localVersion Version
+	localErr     error
+)
+
+// Local returns the version reported by `go env GOVERSION`. The result
+// is cached for the life of the process.
+func Local() (Version, error) {
+	localOnce.Do(func() {
+		out, err := exec.Command("go", "env", "GOVERSION").Output()
+		if err != nil {
+			localErr = fmt.Errorf("failed to detect the Go toolchain: %v", err)
+			return
+		}
+		// Development builds report e.g. "devel go1.23-abcdef".
+		fields := strings.Fields(string(out))
+		for _, field := range fields {
+			if v, err := Parse(strings.SplitN(field, "-", 2)[0]); err == nil {
+				localVersion = v
+				return
+			}
+		}
+		localErr = fmt.Errorf("failed to detect the Go toolchain: unexpected GOVERSION %q", strings.TrimSpace(string(out)))
+	})
+	return localVersion, localErr
+}
+
+// Resolve returns the requested version, or the local toolchain's when
+// requested is empty. When parsing or detection fails it returns Fallback
+// together with the error.
+func Resolve(requested string) (Version, error) {
+	if requested != "" {
+		v, err := Parse(requested)
+		if err != nil {
+			return Fallback, err
+		}
+		return v, nil
+	}
+
+	v, err := Local()
+	if err != nil {
+		return Fallback, err
+	}
+	return v, nil
+}
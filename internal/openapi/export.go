@@ -0,0 +1,963 @@
+package openapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/constant"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/fs"
+	"net/http"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RoutesDir is the package of a project that registers its routes.
+const RoutesDir = "internal/routes"
+
+// Export describes the API of the project in fsys, whose module path is
+// module, as an OpenAPI 3 document. It follows the gin route registrations
+// of the exported functions in RoutesDir through router groups, and reads
+// the handlers they register for path, query and header parameters, JSON
+// request bodies and the status and body of each response. The document
+// is a skeleton to refine by hand: warnings list what could not be
+// inferred.
+func Export(fsys fs.FS, module string) (doc []byte, warnings []string, err error) {
+	e := newExporter(fsys, module)
+	pkg, err := e.load(path.Join(module, RoutesDir))
+	if err != nil {
+		return nil, nil, err
+	}
+	if pkg == nil {
+		return nil, nil, fmt.Errorf("%s not found: run sova openapi export in the root of an api project", RoutesDir)
+	}
+
+	for _, file := range pkg.files {
+		for _, d := range file.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !fn.Name.IsExported() || fn.Body == nil {
+				continue
+			}
+			groups := map[string]string{}
+			for _, field := range fn.Type.Params.List {
+				if isRouter(field.Type) {
+					for _, name := range field.Names {
+						groups[name.Name] = ""
+					}
+				}
+			}
+			if len(groups) > 0 {
+				e.walkRoutes(pkg, fn, groups, map[*ast.FuncDecl]bool{})
+			}
+		}
+	}
+	if len(e.spec.Paths.Keys) == 0 {
+		return nil, nil, fmt.Errorf("found no routes registered in %s", RoutesDir)
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(e.spec); err != nil {
+		return nil, nil, fmt.Errorf("failed to encode OpenAPI document: %v", err)
+	}
+	return buf.Bytes(), e.warnings, nil
+}
+
+// exporter type-checks the packages of a project from source. Packages
+// outside the module and the standard library cannot be loaded, so they
+// are replaced by empty packages and the errors they cause are ignored:
+// the types of the project itself are all Export needs.
+type exporter struct {
+	fsys     fs.FS
+	module   string
+	fset     *token.FileSet
+	std      types.Importer
+	pkgs     map[string]*exportPackage
+	funcs    map[token.Pos]funcDecl
+	spec     *Spec
+	schemas  map[*types.TypeName]string
+	opIDs    names
+	warnings []string
+	seen     map[string]bool
+}
+
+type exportPackage struct {
+	types *types.Package
+	files []*ast.File
+	info  *types.Info
+}
+
+// funcDecl is a function of the project with the package declaring it.
+type funcDecl struct {
+	decl *ast.FuncDecl
+	pkg  *exportPackage
+}
+
+func newExporter(fsys fs.FS, module string) *exporter {
+	// Projects never need cgo to be described; checking the standard
+	// library without it avoids running the cgo tool.
+	build.Default.CgoEnabled = false
+
+	fset := token.NewFileSet()
+	return &exporter{
+		fsys:   fsys,
+		module: module,
+		fset:   fset,
+		std:    importer.ForCompiler(fset, "source", nil),
+		pkgs:   map[string]*exportPackage{},
+		funcs:  map[token.Pos]funcDecl{},
+		spec: &Spec{
+			OpenAPI: "3.0.3",
+			Info: Info{
+				Title:       path.Base(module),
+				Description: "Exported by sova openapi export from the routes of " + module + ".",
+				Version:     "0.1.0",
+			},
+		},
+		schemas: map[*types.TypeName]string{},
+		opIDs:   names{},
+		seen:    map[string]bool{},
+	}
+}
+
+func (e *exporter) warn(format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...)
+	if !e.seen[message] {
+		e.seen[message] = true
+		e.warnings = append(e.warnings, message)
+	}
+}
+
+func (e *exporter) Import(importPath string) (*types.Package, error) {
+	if importPath == e.module || strings.HasPrefix(importPath, e.module+"/") {
+		pkg, err := e.load(importPath)
+		if err != nil || pkg == nil {
+			return nil, fmt.Errorf("cannot load %s", importPath)
+		}
+		return pkg.types, nil
+	}
+	if !strings.Contains(strings.Split(importPath, "/")[0], ".") {
+		if pkg, err := e.std.Import(importPath); err == nil {
+			return pkg, nil
+		}
+	}
+	pkg := types.NewPackage(importPath, packageName(importPath))
+	pkg.MarkComplete()
+	return pkg, nil
+}
+
+// packageName guesses the name of the package at importPath, such as gin
+// for github.com/gin-gonic/gin and redis for github.com/redis/go-redis/v9.
+func packageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && len(name) > 1 && name[0] == 'v' && strings.Trim(name[1:], "0123456789") == "" {
+		name = elems[len(elems)-2]
+	}
+	if i := strings.Index(name, ".v"); i > 0 {
+		name = name[:i]
+	}
+	return strings.TrimPrefix(name, "go-")
+}
+
+// load type-checks the package at importPath of the module, or returns nil
+// when the project has no such directory.
+func (e *exporter) load(importPath string) (*exportPackage, error) {
+	if pkg, ok := e.pkgs[importPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", importPath)
+		}
+		return pkg, nil
+	}
+	e.pkgs[importPath] = nil
+
+	dir := strings.TrimPrefix(strings.TrimPrefix(importPath, e.module), "/")
+	if dir == "" {
+		dir = "."
+	}
+	entries, err := fs.ReadDir(e.fsys, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		delete(e.pkgs, importPath)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", dir, err)
+	}
+
+	pkg := &exportPackage{
+		info: &types.Info{
+			Types: map[ast.Expr]types.TypeAndValue{},
+			Defs:  map[*ast.Ident]types.Object{},
+			Uses:  map[*ast.Ident]types.Object{},
+		},
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		filePath := path.Join(dir, name)
+		data, err := fs.ReadFile(e.fsys, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", filePath, err)
+		}
+		file, err := parser.ParseFile(e.fset, filePath, data, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", filePath, err)
+		}
+		pkg.files = append(pkg.files, file)
+	}
+	if len(pkg.files) == 0 {
+		delete(e.pkgs, importPath)
+		return nil, nil
+	}
+
+	conf := types.Config{
+		Importer: e,
+		// Errors come from the packages that could not be loaded
+		Error: func(error) {},
+	}
+	pkg.types, _ = conf.Check(importPath, e.fset, pkg.files, pkg.info)
+	e.pkgs[importPath] = pkg
+
+	for _, file := range pkg.files {
+		for _, d := range file.Decls {
+			if fn, ok := d.(*ast.FuncDecl); ok {
+				e.funcs[fn.Name.Pos()] = funcDecl{decl: fn, pkg: pkg}
+			}
+		}
+	}
+	return pkg, nil
+}
+
+// isRouter reports whether expr is one of the gin types routes are
+// registered on.
+func isRouter(expr ast.Expr) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	if !ok || x.Name != "gin" {
+		return false
+	}
+	switch sel.Sel.Name {
+	case "Engine", "RouterGroup", "IRoutes", "IRouter":
+		return true
+	}
+	return false
+}
+
+// walkRoutes records the routes fn registers. groups maps the variables
+// holding a router or router group to their path prefix.
+func (e *exporter) walkRoutes(pkg *exportPackage, fn *ast.FuncDecl, groups map[string]string, visiting map[*ast.FuncDecl]bool) {
+	if visiting[fn] {
+		return
+	}
+	visiting[fn] = true
+	defer delete(visiting, fn)
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != len(n.Rhs) {
+				return true
+			}
+			for i, rhs := range n.Rhs {
+				if lhs, ok := n.Lhs[i].(*ast.Ident); ok {
+					if prefix, ok := e.groupPrefix(pkg, rhs, groups); ok {
+						groups[lhs.Name] = prefix
+					}
+				}
+			}
+		case *ast.CallExpr:
+			e.routeCall(pkg, n, groups, visiting)
+		}
+		return true
+	})
+}
+
+// groupPrefix returns the path prefix of a router expression: a variable
+// in groups, or a call of Group on a router expression.
+func (e *exporter) groupPrefix(pkg *exportPackage, expr ast.Expr, groups map[string]string) (string, bool) {
+	switch expr := expr.(type) {
+	case *ast.ParenExpr:
+		return e.groupPrefix(pkg, expr.X, groups)
+	case *ast.Ident:
+		prefix, ok := groups[expr.Name]
+		return prefix, ok
+	case *ast.CallExpr:
+		sel, ok := expr.Fun.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Group" || len(expr.Args) == 0 {
+			return "", false
+		}
+		base, ok := e.groupPrefix(pkg, sel.X, groups)
+		if !ok {
+			return "", false
+		}
+		rel, ok := e.stringValue(pkg, expr.Args[0])
+		if !ok {
+			e.warn("%s: the prefix of the router group is not a constant", e.position(expr.Args[0]))
+			return "", false
+		}
+		return joinPaths(base, rel), true
+	}
+	return "", false
+}
+
+func (e *exporter) routeCall(pkg *exportPackage, call *ast.CallExpr, groups map[string]string, visiting map[*ast.FuncDecl]bool) {
+	if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+		if prefix, ok := e.groupPrefix(pkg, sel.X, groups); ok {
+			args := call.Args
+			switch method := sel.Sel.Name; method {
+			case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS":
+				if len(args) >= 2 {
+					e.route(pkg, method, prefix, args[0], args[len(args)-1])
+				}
+			case "Handle":
+				if len(args) >= 3 {
+					if m, ok := e.stringValue(pkg, args[0]); ok {
+						e.route(pkg, strings.ToUpper(m), prefix, args[1], args[len(args)-1])
+					} else {
+						e.warn("%s: the method of the route is not a constant", e.position(args[0]))
+					}
+				}
+			case "Any", "Match":
+				e.warn("%s: routes registered with %s serve several methods and are not exported", e.position(call), method)
+			}
+			return
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == "openapi" && sel.Sel.Name == "RegisterRoutes" {
+			e.warn("%s: the operations registered by openapi.RegisterRoutes are described by the spec they were generated from and are not exported", e.position(call))
+		}
+		return
+	}
+
+	// Follow functions of the package that are passed a router
+	id, ok := call.Fun.(*ast.Ident)
+	if !ok {
+		return
+	}
+	fn, ok := e.funcs[objPos(pkg.info.Uses[id])]
+	if !ok || fn.pkg != pkg || fn.decl.Body == nil {
+		return
+	}
+	inner := map[string]string{}
+	i := 0
+	for _, field := range fn.decl.Type.Params.List {
+		for _, name := range field.Names {
+			if i < len(call.Args) {
+				if prefix, ok := e.groupPrefix(pkg, call.Args[i], groups); ok {
+					inner[name.Name] = prefix
+				}
+			}
+			i++
+		}
+	}
+	if len(inner) > 0 {
+		e.walkRoutes(pkg, fn.decl, inner, visiting)
+	}
+}
+
+func objPos(obj types.Object) token.Pos {
+	if obj == nil {
+		return token.NoPos
+	}
+	return obj.Pos()
+}
+
+func (e *exporter) position(n ast.Node) string {
+	return e.fset.Position(n.Pos()).String()
+}
+
+func (e *exporter) stringValue(pkg *exportPackage, expr ast.Expr) (string, bool) {
+	if tv, ok := pkg.info.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+		return constant.StringVal(tv.Value), true
+	}
+	if lit, ok := expr.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+		s, err := strconv.Unquote(lit.Value)
+		return s, err == nil
+	}
+	return "", false
+}
+
+func (e *exporter) intValue(pkg *exportPackage, expr ast.Expr) (int, bool) {
+	if tv, ok := pkg.info.Types[expr]; ok && tv.Value != nil {
+		if n, ok := constant.Int64Val(constant.ToInt(tv.Value)); ok {
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// joinPaths joins a group prefix and a relative path the way gin does.
+func joinPaths(prefix, rel string) string {
+	if rel == "" {
+		return prefix
+	}
+	joined := strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(rel, "/")
+	return joined
+}
+
+// route records the operation of a route with the handler expression h.
+func (e *exporter) route(pkg *exportPackage, method, prefix string, pathExpr, h ast.Expr) {
+	rel, ok := e.stringValue(pkg, pathExpr)
+	if !ok {
+		e.warn("%s: the path of the route is not a constant", e.position(pathExpr))
+		return
+	}
+	ginPath := joinPaths(prefix, rel)
+	if ginPath == "" {
+		ginPath = "/"
+	}
+
+	// gin writes parameters as :name and *name
+	segments := strings.Split(ginPath, "/")
+	var params []string
+	for i, s := range segments {
+		if strings.HasPrefix(s, ":") || strings.HasPrefix(s, "*") {
+			params = append(params, s[1:])
+			segments[i] = "{" + s[1:] + "}"
+		}
+	}
+	specPath := strings.Join(segments, "/")
+
+	item := e.spec.Paths.Values[specPath]
+	if item == nil {
+		item = &PathItem{}
+		e.spec.Paths.set(specPath, item)
+	}
+	slot := item.operation(method)
+	if slot == nil {
+		e.warn("%s %s: method %s is not supported by OpenAPI", method, ginPath, method)
+		return
+	}
+	if *slot != nil {
+		e.warn("%s %s is registered twice; the first registration is exported", method, ginPath)
+		return
+	}
+
+	op := &Operation{}
+	for _, name := range params {
+		op.Parameters = append(op.Parameters, &Parameter{Name: name, In: "path", Required: true, Schema: &Schema{Type: schemaType{Name: "string"}}})
+	}
+
+	handler, ok := e.handler(pkg, h)
+	if ok {
+		op.OperationID = e.opIDs.unique(operationID(handler.name, method, specPath))
+		op.Summary = handler.summary()
+		e.readHandler(handler, op)
+	} else {
+		op.OperationID = e.opIDs.unique(operationID("", method, specPath))
+		e.warn("%s %s: the handler is not a gin handler function sova can read; describe its parameters and responses by hand", method, ginPath)
+	}
+	if len(op.Responses.Keys) == 0 {
+		op.Responses.set("200", &Response{Description: "OK"})
+	}
+	*slot = op
+}
+
+func (p *PathItem) operation(method string) **Operation {
+	switch method {
+	case "GET":
+		return &p.Get
+	case "PUT":
+		return &p.Put
+	case "POST":
+		return &p.Post
+	case "DELETE":
+		return &p.Delete
+	case "OPTIONS":
+		return &p.Options
+	case "HEAD":
+		return &p.Head
+	case "PATCH":
+		return &p.Patch
+	case "TRACE":
+		return &p.Trace
+	}
+	return nil
+}
+
+// operationID derives the operationId of a route from the name of its
+// handler, or from its method and path: PingHandler gives ping.
+func operationID(handler, method, specPath string) string {
+	name := strings.TrimSuffix(handler, "Handler")
+	if name == "" {
+		name = goName(strings.ToLower(method) + " " + specPath)
+	}
+	r := []rune(goName(name))
+	// Lower the leading initialism or letter: APIKeys gives apiKeys
+	i := 0
+	for i < len(r) && unicode.IsUpper(r[i]) && (i == 0 || i+1 == len(r) || unicode.IsUpper(r[i+1])) {
+		r[i] = unicode.ToLower(r[i])
+		i++
+	}
+	return string(r)
+}
+
+// summary returns the first sentence of the doc comment of h, without
+// the name of the function it starts with.
+func (h *handlerFunc) summary() string {
+	doc := strings.Join(strings.Fields(h.doc), " ")
+	if i := strings.Index(doc, ". "); i >= 0 {
+		doc = doc[:i]
+	}
+	doc = strings.TrimSuffix(doc, ".")
+	if h.decl != nil {
+		doc = strings.TrimPrefix(doc, h.decl.Name.Name+" ")
+	}
+	if doc == "" {
+		return ""
+	}
+	r := []rune(doc)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// handlerFunc is the body of a gin handler.
+type handlerFunc struct {
+	name string
+	doc  string
+	decl *ast.FuncDecl // the function declaring it, if any
+	fn   *ast.FuncType
+	body *ast.BlockStmt
+	pkg  *exportPackage
+}
+
+// handler finds the function a route registers: a function or method of
+// the project, a function literal, or the function literal returned by a
+// call of a function of the project.
+func (e *exporter) handler(pkg *exportPackage, h ast.Expr) (*handlerFunc, bool) {
+	switch h := h.(type) {
+	case *ast.FuncLit:
+		return &handlerFunc{fn: h.Type, body: h.Body, pkg: pkg}, true
+	case *ast.Ident, *ast.SelectorExpr:
+		fn, ok := e.funcs[objPos(pkg.info.Uses[identOf(h)])]
+		if !ok || fn.decl.Body == nil {
+			return nil, false
+		}
+		return &handlerFunc{name: funcName(fn.decl), doc: fn.decl.Doc.Text(), decl: fn.decl, fn: fn.decl.Type, body: fn.decl.Body, pkg: fn.pkg}, true
+	case *ast.CallExpr:
+		fn, ok := e.funcs[objPos(pkg.info.Uses[identOf(h.Fun)])]
+		if !ok || fn.decl.Body == nil {
+			return nil, false
+		}
+		var lit *ast.FuncLit
+		ast.Inspect(fn.decl.Body, func(n ast.Node) bool {
+			if ret, ok := n.(*ast.ReturnStmt); ok && lit == nil && len(ret.Results) == 1 {
+				lit, _ = ret.Results[0].(*ast.FuncLit)
+			}
+			return lit == nil
+		})
+		if lit == nil {
+			return nil, false
+		}
+		return &handlerFunc{name: funcName(fn.decl), doc: fn.decl.Doc.Text(), decl: fn.decl, fn: lit.Type, body: lit.Body, pkg: fn.pkg}, true
+	}
+	return nil, false
+}
+
+// funcName returns the name of fn, prefixed by the type of its receiver
+// for methods: Users.Get gives UsersGet. A Handler or Handlers suffix of
+// the type is dropped, so Handlers.Ping gives Ping and UsersHandler.Get
+// gives UsersGet.
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	typ := fn.Recv.List[0].Type
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+	if id, ok := typ.(*ast.Ident); ok {
+		return strings.TrimSuffix(strings.TrimSuffix(id.Name, "Handlers"), "Handler") + fn.Name.Name
+	}
+	return fn.Name.Name
+}
+
+func identOf(expr ast.Expr) *ast.Ident {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return expr
+	case *ast.SelectorExpr:
+		return expr.Sel
+	}
+	return nil
+}
+
+// readHandler adds the parameters, request body and responses h uses to
+// op.
+func (e *exporter) readHandler(h *handlerFunc, op *Operation) {
+	ctx := contextParam(h.fn)
+	if ctx == "" {
+		return
+	}
+	info := h.pkg.info
+	params := map[string]bool{}
+	for _, p := range op.Parameters {
+		params[p.In+" "+p.Name] = true
+	}
+	addParam := func(name, in string, schema *Schema) {
+		if params[in+" "+name] {
+			return
+		}
+		params[in+" "+name] = true
+		op.Parameters = append(op.Parameters, &Parameter{Name: name, In: in, Schema: schema})
+	}
+	stringSchema := func() *Schema { return &Schema{Type: schemaType{Name: "string"}} }
+
+	ast.Inspect(h.body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if x, ok := sel.X.(*ast.Ident); !ok || x.Name != ctx {
+			return true
+		}
+		args := call.Args
+		arg := func(i int) (string, bool) {
+			if i >= len(args) {
+				return "", false
+			}
+			return e.stringValue(h.pkg, args[i])
+		}
+
+		switch sel.Sel.Name {
+		case "Query", "DefaultQuery", "GetQuery":
+			if name, ok := arg(0); ok {
+				addParam(name, "query", stringSchema())
+			}
+		case "QueryArray", "GetQueryArray":
+			if name, ok := arg(0); ok {
+				addParam(name, "query", &Schema{Type: schemaType{Name: "array"}, Items: stringSchema()})
+			}
+		case "GetHeader":
+			if name, ok := arg(0); ok {
+				addParam(name, "header", stringSchema())
+			}
+		case "ShouldBindJSON", "BindJSON", "ShouldBind", "Bind":
+			if len(args) == 1 && op.RequestBody == nil {
+				op.RequestBody = &RequestBody{
+					Required: true,
+					Content:  map[string]*MediaType{"application/json": {Schema: e.schemaOf(info.TypeOf(args[0]))}},
+				}
+			}
+		case "JSON", "IndentedJSON", "PureJSON", "SecureJSON", "AbortWithStatusJSON":
+			if len(args) == 2 {
+				e.responses(h, op, args[0], "application/json", e.bodySchema(h.pkg, args[1]))
+			}
+		case "String":
+			if len(args) >= 2 {
+				e.responses(h, op, args[0], "text/plain", stringSchema())
+			}
+		case "Data":
+			if len(args) == 3 {
+				contentType, ok := arg(1)
+				if !ok {
+					contentType = "application/octet-stream"
+				}
+				e.responses(h, op, args[0], contentType, &Schema{Type: schemaType{Name: "string"}, Format: "binary"})
+			}
+		case "Status", "AbortWithStatus":
+			if len(args) == 1 {
+				e.responses(h, op, args[0], "", nil)
+			}
+		}
+		return true
+	})
+}
+
+// contextParam returns the name of the *gin.Context parameter of fn.
+func contextParam(fn *ast.FuncType) string {
+	for _, field := range fn.Params.List {
+		star, ok := field.Type.(*ast.StarExpr)
+		if !ok {
+			continue
+		}
+		sel, ok := star.X.(*ast.SelectorExpr)
+		if !ok || sel.Sel.Name != "Context" {
+			continue
+		}
+		if len(field.Names) > 0 {
+			return field.Names[0].Name
+		}
+	}
+	return ""
+}
+
+// responses records the responses of a call answering with the status
+// expr, keeping the first response of each status. A status variable
+// gives a response for each constant assigned to it in the handler.
+func (e *exporter) responses(h *handlerFunc, op *Operation, statusExpr ast.Expr, contentType string, schema *Schema) {
+	codes := []string{"default"}
+	if status, ok := e.intValue(h.pkg, statusExpr); ok {
+		codes = []string{strconv.Itoa(status)}
+	} else if statuses := e.assigned(h, statusExpr); len(statuses) > 0 {
+		codes = statuses
+	}
+
+	for _, code := range codes {
+		if _, ok := op.Responses.Values[code]; ok {
+			continue
+		}
+		status, _ := strconv.Atoi(code)
+		description := http.StatusText(status)
+		if description == "" {
+			description = "Response"
+		}
+		resp := &Response{Description: description}
+		if contentType != "" {
+			resp.Content = map[string]*MediaType{contentType: {Schema: schema}}
+		}
+		op.Responses.set(code, resp)
+	}
+	sort.SliceStable(op.Responses.Keys, func(i, j int) bool {
+		return op.Responses.Keys[i] < op.Responses.Keys[j]
+	})
+}
+
+// assigned returns the integer constants assigned to the variable expr
+// in the body of h.
+func (e *exporter) assigned(h *handlerFunc, expr ast.Expr) []string {
+	id, ok := expr.(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	v := h.pkg.info.Uses[id]
+	if v == nil {
+		return nil
+	}
+
+	var codes []string
+	add := func(lhs []ast.Expr, rhs []ast.Expr) {
+		if len(lhs) != len(rhs) {
+			return
+		}
+		for i, l := range lhs {
+			lid, ok := l.(*ast.Ident)
+			if !ok || (h.pkg.info.Defs[lid] != v && h.pkg.info.Uses[lid] != v) {
+				continue
+			}
+			if status, ok := e.intValue(h.pkg, rhs[i]); ok {
+				codes = append(codes, strconv.Itoa(status))
+			}
+		}
+	}
+	ast.Inspect(h.body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			add(n.Lhs, n.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(n.Names))
+			for i, name := range n.Names {
+				lhs[i] = name
+			}
+			add(lhs, n.Values)
+		}
+		return true
+	})
+	return codes
+}
+
+// bodySchema returns the schema of a response body expression. gin.H
+// literals become objects with their keys as properties.
+func (e *exporter) bodySchema(pkg *exportPackage, expr ast.Expr) *Schema {
+	if lit, ok := expr.(*ast.CompositeLit); ok && isGinH(lit.Type) {
+		s := &Schema{Type: schemaType{Name: "object"}}
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if key, ok := e.stringValue(pkg, kv.Key); ok {
+				s.Properties.set(key, e.bodySchema(pkg, kv.Value))
+			}
+		}
+		return s
+	}
+	return e.schemaOf(pkg.info.TypeOf(expr))
+}
+
+func isGinH(expr ast.Expr) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "H" {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == "gin"
+}
+
+// schemaOf returns the schema of values of t. Named types of the project
+// become components.
+func (e *exporter) schemaOf(t types.Type) *Schema {
+	switch t := t.(type) {
+	case nil:
+		return &Schema{}
+	case *types.Pointer:
+		return e.schemaOf(t.Elem())
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() == nil {
+			return e.schemaOf(t.Underlying())
+		}
+		switch obj.Pkg().Path() + "." + obj.Name() {
+		case "time.Time":
+			return &Schema{Type: schemaType{Name: "string"}, Format: "date-time"}
+		case "encoding/json.RawMessage":
+			return &Schema{}
+		}
+		if p := obj.Pkg().Path(); p == e.module || strings.HasPrefix(p, e.module+"/") {
+			return &Schema{Ref: "#/components/schemas/" + e.component(t)}
+		}
+		return e.schemaOf(t.Underlying())
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Slice:
+		if b, ok := t.Elem().(*types.Basic); ok && b.Kind() == types.Byte {
+			return &Schema{Type: schemaType{Name: "string"}, Format: "byte"}
+		}
+		return &Schema{Type: schemaType{Name: "array"}, Items: e.schemaOf(t.Elem())}
+	case *types.Array:
+		return &Schema{Type: schemaType{Name: "array"}, Items: e.schemaOf(t.Elem())}
+	case *types.Map:
+		return &Schema{Type: schemaType{Name: "object"}, AdditionalProperties: additional{Schema: e.schemaOf(t.Elem())}}
+	case *types.Struct:
+		return e.structSchema(t)
+	}
+	return &Schema{}
+}
+
+func basicSchema(t *types.Basic) *Schema {
+	info := t.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return &Schema{Type: schemaType{Name: "boolean"}}
+	case info&types.IsString != 0:
+		return &Schema{Type: schemaType{Name: "string"}}
+	case info&types.IsInteger != 0:
+		s := &Schema{Type: schemaType{Name: "integer"}}
+		switch t.Kind() {
+		case types.Int32, types.Uint32:
+			s.Format = "int32"
+		case types.Int64, types.Uint64:
+			s.Format = "int64"
+		}
+		return s
+	case info&types.IsFloat != 0:
+		if t.Kind() == types.Float32 {
+			return &Schema{Type: schemaType{Name: "number"}, Format: "float"}
+		}
+		return &Schema{Type: schemaType{Name: "number"}, Format: "double"}
+	}
+	return &Schema{}
+}
+
+// component declares the schema of the named type t and returns its name.
+func (e *exporter) component(t *types.Named) string {
+	obj := t.Obj()
+	if name, ok := e.schemas[obj]; ok {
+		return name
+	}
+	name := obj.Name()
+	if _, taken := e.spec.Components.Schemas.Values[name]; taken {
+		name = goName(obj.Pkg().Name() + " " + obj.Name())
+	}
+	e.schemas[obj] = name
+
+	// Declare the name first, so recursive types refer to it
+	s := &Schema{}
+	e.spec.Components.Schemas.set(name, s)
+	*s = *e.schemaOf(t.Underlying())
+	s.Enum = enumValues(t)
+	return name
+}
+
+// enumValues returns the constants the package of t declares of type t.
+func enumValues(t *types.Named) []interface{} {
+	scope := t.Obj().Pkg().Scope()
+	var values []interface{}
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), t) {
+			continue
+		}
+		switch c.Val().Kind() {
+		case constant.String:
+			values = append(values, constant.StringVal(c.Val()))
+		case constant.Int:
+			if n, ok := constant.Int64Val(c.Val()); ok {
+				values = append(values, n)
+			}
+		}
+	}
+	return values
+}
+
+// structSchema returns the object schema of the JSON encoding of t.
+func (e *exporter) structSchema(t *types.Struct) *Schema {
+	s := &Schema{Type: schemaType{Name: "object"}}
+	e.addFields(s, t)
+	return s
+}
+
+func (e *exporter) addFields(s *Schema, t *types.Struct) {
+	for i := 0; i < t.NumFields(); i++ {
+		field := t.Field(i)
+		name, opts := jsonTag(t.Tag(i))
+		if name == "-" && opts == "" {
+			continue
+		}
+		if field.Embedded() && name == "" {
+			if embedded, ok := derefStruct(field.Type()); ok {
+				e.addFields(s, embedded)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+		s.Properties.set(name, e.schemaOf(field.Type()))
+		_, pointer := field.Type().(*types.Pointer)
+		if !strings.Contains(","+opts+",", ",omitempty,") && !pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+func derefStruct(t types.Type) (*types.Struct, bool) {
+	if p, ok := t.(*types.Pointer); ok {
+		t = p.Elem()
+	}
+	st, ok := t.Underlying().(*types.Struct)
+	return st, ok
+}
+
+// jsonTag splits the json key of a struct tag into the name and options.
+func jsonTag(tag string) (name, opts string) {
+	value := reflect.StructTag(tag).Get("json")
+	name, opts, _ = strings.Cut(value, ",")
+	return name, opts
+}
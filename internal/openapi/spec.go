@@ -0,0 +1,554 @@
+// Package openapi generates the server side of an API from an OpenAPI 3
+// spec: typed models, a handler interface per operation, gin route
+// registration with request validation, and a docs page serving the spec.
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is the part of an OpenAPI 3 document sova generates code from.
+type Spec struct {
+	OpenAPI    string             `yaml:"openapi"`
+	Swagger    string             `yaml:"swagger,omitempty"`
+	Info       Info               `yaml:"info,omitempty"`
+	Servers    []Server           `yaml:"servers,omitempty"`
+	Paths      ordered[*PathItem] `yaml:"paths"`
+	Components Components         `yaml:"components,omitempty"`
+
+	// Raw is the document as it was read.
+	Raw []byte `yaml:"-"`
+	// JSON reports whether the document is written in JSON.
+	JSON bool `yaml:"-"`
+
+	operations []*Operation
+}
+
+type Info struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description,omitempty"`
+	Version     string `yaml:"version"`
+}
+
+type Server struct {
+	URL string `yaml:"url,omitempty"`
+}
+
+type Components struct {
+	Schemas       ordered[*Schema]        `yaml:"schemas,omitempty"`
+	Parameters    map[string]*Parameter   `yaml:"parameters,omitempty"`
+	RequestBodies map[string]*RequestBody `yaml:"requestBodies,omitempty"`
+	Responses     map[string]*Response    `yaml:"responses,omitempty"`
+}
+
+type PathItem struct {
+	Ref        string       `yaml:"$ref,omitempty"`
+	Parameters []*Parameter `yaml:"parameters,omitempty"`
+	Get        *Operation   `yaml:"get,omitempty"`
+	Put        *Operation   `yaml:"put,omitempty"`
+	Post       *Operation   `yaml:"post,omitempty"`
+	Delete     *Operation   `yaml:"delete,omitempty"`
+	Options    *Operation   `yaml:"options,omitempty"`
+	Head       *Operation   `yaml:"head,omitempty"`
+	Patch      *Operation   `yaml:"patch,omitempty"`
+	Trace      *Operation   `yaml:"trace,omitempty"`
+}
+
+// Operation is one method of a path. Method, Path and the parameters
+// inherited from the path are filled in by Load.
+type Operation struct {
+	OperationID string             `yaml:"operationId,omitempty"`
+	Summary     string             `yaml:"summary,omitempty"`
+	Description string             `yaml:"description,omitempty"`
+	Parameters  []*Parameter       `yaml:"parameters,omitempty"`
+	RequestBody *RequestBody       `yaml:"requestBody,omitempty"`
+	Responses   ordered[*Response] `yaml:"responses"`
+
+	Method string `yaml:"-"`
+	Path   string `yaml:"-"`
+}
+
+type Parameter struct {
+	Ref         string  `yaml:"$ref,omitempty"`
+	Name        string  `yaml:"name,omitempty"`
+	In          string  `yaml:"in,omitempty"`
+	Description string  `yaml:"description,omitempty"`
+	Required    bool    `yaml:"required,omitempty"`
+	Schema      *Schema `yaml:"schema,omitempty"`
+}
+
+type RequestBody struct {
+	Ref      string                `yaml:"$ref,omitempty"`
+	Required bool                  `yaml:"required,omitempty"`
+	Content  map[string]*MediaType `yaml:"content,omitempty"`
+}
+
+type Response struct {
+	Ref         string                `yaml:"$ref,omitempty"`
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `yaml:"schema,omitempty"`
+}
+
+// Schema is a JSON schema as OpenAPI 3.0 and 3.1 write them, limited to
+// the keywords that shape Go types and request validation.
+type Schema struct {
+	Ref                  string           `yaml:"$ref,omitempty"`
+	Type                 schemaType       `yaml:"type,omitempty"`
+	Format               string           `yaml:"format,omitempty"`
+	Description          string           `yaml:"description,omitempty"`
+	Properties           ordered[*Schema] `yaml:"properties,omitempty"`
+	Required             []string         `yaml:"required,omitempty"`
+	Items                *Schema          `yaml:"items,omitempty"`
+	AdditionalProperties additional       `yaml:"additionalProperties,omitempty"`
+	AllOf                []*Schema        `yaml:"allOf,omitempty"`
+	OneOf                []*Schema        `yaml:"oneOf,omitempty"`
+	AnyOf                []*Schema        `yaml:"anyOf,omitempty"`
+	Enum                 []interface{}    `yaml:"enum,omitempty"`
+	Nullable             bool             `yaml:"nullable,omitempty"`
+	MinLength            *int             `yaml:"minLength,omitempty"`
+	MaxLength            *int             `yaml:"maxLength,omitempty"`
+	Pattern              string           `yaml:"pattern,omitempty"`
+	Minimum              *float64         `yaml:"minimum,omitempty"`
+	Maximum              *float64         `yaml:"maximum,omitempty"`
+	MinItems             *int             `yaml:"minItems,omitempty"`
+	MaxItems             *int             `yaml:"maxItems,omitempty"`
+}
+
+// schemaType is the type keyword, a name in OpenAPI 3.0 and a name or a
+// list of names in 3.1, where "null" makes the schema nullable.
+type schemaType struct {
+	Name     string
+	Nullable bool
+}
+
+func (t *schemaType) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		t.Name = node.Value
+		return nil
+	}
+	var names []string
+	if err := node.Decode(&names); err != nil {
+		return err
+	}
+	for _, name := range names {
+		switch {
+		case name == "null":
+			t.Nullable = true
+		case t.Name == "":
+			t.Name = name
+		default:
+			return fmt.Errorf("line %d: schemas of several types are not supported", node.Line)
+		}
+	}
+	return nil
+}
+
+func (t schemaType) IsZero() bool {
+	return t.Name == "" && !t.Nullable
+}
+
+func (t schemaType) MarshalYAML() (interface{}, error) {
+	if t.Nullable {
+		return []string{t.Name, "null"}, nil
+	}
+	return t.Name, nil
+}
+
+// additional is additionalProperties, either a bool or a schema.
+type additional struct {
+	Schema *Schema
+}
+
+func (a *additional) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return nil
+	}
+	a.Schema = &Schema{}
+	return node.Decode(a.Schema)
+}
+
+func (a additional) IsZero() bool {
+	return a.Schema == nil
+}
+
+func (a additional) MarshalYAML() (interface{}, error) {
+	return a.Schema, nil
+}
+
+// ordered is a mapping that remembers the order of its keys, so generated
+// code follows the order of the spec.
+type ordered[T any] struct {
+	Keys   []string
+	Values map[string]T
+}
+
+func (o *ordered[T]) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping", node.Line)
+	}
+	o.Values = make(map[string]T, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i].Value
+		var value T
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+		if _, ok := o.Values[key]; !ok {
+			o.Keys = append(o.Keys, key)
+		}
+		o.Values[key] = value
+	}
+	return nil
+}
+
+// set adds or replaces the value of key.
+func (o *ordered[T]) set(key string, value T) {
+	if o.Values == nil {
+		o.Values = map[string]T{}
+	}
+	if _, ok := o.Values[key]; !ok {
+		o.Keys = append(o.Keys, key)
+	}
+	o.Values[key] = value
+}
+
+func (o ordered[T]) IsZero() bool {
+	return len(o.Keys) == 0
+}
+
+func (o ordered[T]) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, key := range o.Keys {
+		// Encoding the key quotes keys such as status codes that would
+		// read back as numbers
+		var k, value yaml.Node
+		if err := k.Encode(key); err != nil {
+			return nil, err
+		}
+		if err := value.Encode(o.Values[key]); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &k, &value)
+	}
+	return node, nil
+}
+
+// LoadFile reads and loads the spec at path.
+func LoadFile(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OpenAPI spec: %v", err)
+	}
+	spec, err := Load(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return spec, nil
+}
+
+// Load parses an OpenAPI 3 document in YAML or JSON and checks that sova
+// can generate code from it.
+func Load(data []byte) (*Spec, error) {
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %v", err)
+	}
+	if spec.Swagger != "" {
+		return nil, fmt.Errorf("swagger %s documents are not supported: convert the spec to OpenAPI 3", spec.Swagger)
+	}
+	if !strings.HasPrefix(spec.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q: sova supports OpenAPI 3", spec.OpenAPI)
+	}
+	spec.Raw = data
+	spec.JSON = bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+
+	if err := spec.collectOperations(); err != nil {
+		return nil, err
+	}
+	if len(spec.operations) == 0 {
+		return nil, fmt.Errorf("the spec defines no operations")
+	}
+	if err := spec.checkRefs(); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// File is the path of the spec within a generated project.
+func (s *Spec) File() string {
+	if s.JSON {
+		return "api/openapi.json"
+	}
+	return "api/openapi.yaml"
+}
+
+// Operations returns the operations of the spec in the order it declares
+// them.
+func (s *Spec) Operations() []*Operation {
+	return s.operations
+}
+
+// BasePath is the path of the first server URL, which every route is
+// registered under.
+func (s *Spec) BasePath() string {
+	if len(s.Servers) == 0 {
+		return ""
+	}
+	u, err := url.Parse(s.Servers[0].URL)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(u.Path, "/")
+}
+
+var pathParam = regexp.MustCompile(`\{([^}]*)\}`)
+
+func (s *Spec) collectOperations() error {
+	ids := map[string]string{}
+	for _, path := range s.Paths.Keys {
+		item := s.Paths.Values[path]
+		if item == nil {
+			continue
+		}
+		if item.Ref != "" {
+			return fmt.Errorf("path %s: $ref path items are not supported", path)
+		}
+
+		methods := []struct {
+			name string
+			op   *Operation
+		}{
+			{"GET", item.Get}, {"PUT", item.Put}, {"POST", item.Post}, {"DELETE", item.Delete},
+			{"OPTIONS", item.Options}, {"HEAD", item.Head}, {"PATCH", item.Patch}, {"TRACE", item.Trace},
+		}
+		for _, m := range methods {
+			op := m.op
+			if op == nil {
+				continue
+			}
+			op.Method, op.Path = m.name, path
+			if op.OperationID == "" {
+				op.OperationID = strings.ToLower(m.name) + " " + path
+			}
+			name := goName(op.OperationID)
+			if other, ok := ids[name]; ok {
+				return fmt.Errorf("operations %s and %s have the same Go name %s: give them distinct operationIds", other, op, name)
+			}
+			ids[name] = op.String()
+
+			params, err := s.operationParameters(item, op)
+			if err != nil {
+				return err
+			}
+			op.Parameters = params
+			s.operations = append(s.operations, op)
+		}
+	}
+	return nil
+}
+
+// operationParameters resolves the parameters of op, including the ones
+// of its path it does not override, and checks every path segment
+// parameter is declared.
+func (s *Spec) operationParameters(item *PathItem, op *Operation) ([]*Parameter, error) {
+	var params []*Parameter
+	seen := map[string]bool{}
+	add := func(p *Parameter) error {
+		p, err := s.parameter(p)
+		if err != nil {
+			return fmt.Errorf("%s: %v", op, err)
+		}
+		key := p.In + " " + p.Name
+		if seen[key] {
+			return nil
+		}
+		seen[key] = true
+		switch p.In {
+		case "path", "query", "header":
+		default:
+			return fmt.Errorf("%s: %s parameter %s is not supported", op, p.In, p.Name)
+		}
+		params = append(params, p)
+		return nil
+	}
+	for _, p := range op.Parameters {
+		if err := add(p); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range item.Parameters {
+		if err := add(p); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, segment := range strings.Split(op.Path, "/") {
+		m := pathParam.FindStringSubmatch(segment)
+		if m == nil {
+			continue
+		}
+		if m[0] != segment {
+			return nil, fmt.Errorf("%s: path parameters must fill a whole path segment", op)
+		}
+		if !seen["path "+m[1]] {
+			return nil, fmt.Errorf("%s: path parameter %s is not declared", op, m[1])
+		}
+	}
+	return params, nil
+}
+
+func (op *Operation) String() string {
+	return op.Method + " " + op.Path
+}
+
+func (s *Spec) parameter(p *Parameter) (*Parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	name, err := refName(p.Ref, "parameters")
+	if err != nil {
+		return nil, err
+	}
+	resolved, ok := s.Components.Parameters[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %s", p.Ref)
+	}
+	return s.parameter(resolved)
+}
+
+func (s *Spec) requestBody(b *RequestBody) (*RequestBody, error) {
+	if b == nil || b.Ref == "" {
+		return b, nil
+	}
+	name, err := refName(b.Ref, "requestBodies")
+	if err != nil {
+		return nil, err
+	}
+	resolved, ok := s.Components.RequestBodies[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %s", b.Ref)
+	}
+	return s.requestBody(resolved)
+}
+
+func (s *Spec) response(r *Response) (*Response, error) {
+	if r == nil || r.Ref == "" {
+		return r, nil
+	}
+	name, err := refName(r.Ref, "responses")
+	if err != nil {
+		return nil, err
+	}
+	resolved, ok := s.Components.Responses[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %s", r.Ref)
+	}
+	return s.response(resolved)
+}
+
+// schemaRef returns the name and the schema of a $ref to a component
+// schema.
+func (s *Spec) schemaRef(ref string) (string, *Schema, error) {
+	name, err := refName(ref, "schemas")
+	if err != nil {
+		return "", nil, err
+	}
+	schema, ok := s.Components.Schemas.Values[name]
+	if !ok || schema == nil {
+		return "", nil, fmt.Errorf("unresolved $ref %s", ref)
+	}
+	return name, schema, nil
+}
+
+func refName(ref, kind string) (string, error) {
+	prefix := "#/components/" + kind + "/"
+	if !strings.HasPrefix(ref, prefix) {
+		return "", fmt.Errorf("$ref %s is not supported: sova resolves references to %s in the same document", ref, prefix+"...")
+	}
+	return strings.TrimPrefix(ref, prefix), nil
+}
+
+// checkRefs resolves every schema reference, so generation cannot fail on
+// one.
+func (s *Spec) checkRefs() error {
+	var walk func(schema *Schema) error
+	walk = func(schema *Schema) error {
+		if schema == nil {
+			return nil
+		}
+		if schema.Ref != "" {
+			_, _, err := s.schemaRef(schema.Ref)
+			return err
+		}
+		for _, name := range schema.Properties.Keys {
+			if err := walk(schema.Properties.Values[name]); err != nil {
+				return err
+			}
+		}
+		for _, list := range [][]*Schema{schema.AllOf, schema.OneOf, schema.AnyOf} {
+			for _, sub := range list {
+				if err := walk(sub); err != nil {
+					return err
+				}
+			}
+		}
+		if err := walk(schema.Items); err != nil {
+			return err
+		}
+		return walk(schema.AdditionalProperties.Schema)
+	}
+
+	names := append([]string(nil), s.Components.Schemas.Keys...)
+	sort.Strings(names)
+	for _, name := range names {
+		if err := walk(s.Components.Schemas.Values[name]); err != nil {
+			return fmt.Errorf("schema %s: %v", name, err)
+		}
+	}
+
+	for _, op := range s.operations {
+		for _, p := range op.Parameters {
+			if err := walk(p.Schema); err != nil {
+				return fmt.Errorf("%s: %v", op, err)
+			}
+		}
+		body, err := s.requestBody(op.RequestBody)
+		if err != nil {
+			return fmt.Errorf("%s: %v", op, err)
+		}
+		if body != nil {
+			for _, media := range body.Content {
+				if err := walk(media.Schema); err != nil {
+					return fmt.Errorf("%s: %v", op, err)
+				}
+			}
+		}
+		for _, code := range op.Responses.Keys {
+			resp, err := s.response(op.Responses.Values[code])
+			if err != nil {
+				return fmt.Errorf("%s: %v", op, err)
+			}
+			if resp == nil {
+				continue
+			}
+			for _, media := range resp.Content {
+				if err := walk(media.Schema); err != nil {
+					return fmt.Errorf("%s: %v", op, err)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// HandlersDir is the package of a project whose API type implements the
+// handlers of the spec. sova adds stubs to it but never rewrites a file in
+// it.
+const HandlersDir = "internal/handlers"
+
+// handlersType is the type in HandlersDir that implements Handlers.
+const handlersType = "API"
+
+// Stubs returns the files to add to the handlers package in fsys so that
+// its API type implements every operation of spec, keyed by
+// project-relative path: a stub returning 501 Not Implemented for each
+// method API lacks, with a test, and the API type itself when the package
+// does not declare it. module is the module path of the project. Stale lists the
+// methods of API that take a request type of the openapi package but
+// handle no operation of spec any more.
+func Stubs(fsys fs.FS, spec *Spec, module string) (files map[string][]byte, stale []string, err error) {
+	g, err := newGenerator(spec)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkg, err := parseHandlers(fsys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files = map[string][]byte{}
+	fileNames := names{}
+	for name := range pkg.files {
+		fileNames[strings.TrimSuffix(name, ".go")] = true
+	}
+	add := func(base, src string) (string, error) {
+		formatted, err := format.Source([]byte(src))
+		if err != nil {
+			return "", fmt.Errorf("generated %s does not parse: %v", base, err)
+		}
+		// A name ending in _test would make the stub a test file
+		if strings.HasSuffix(base, "_test") {
+			base += "_handler"
+		}
+		name := fileNames.unique(base)
+		files[path.Join(HandlersDir, name+".go")] = formatted
+		return name, nil
+	}
+
+	if !pkg.declared {
+		if _, err := add("api", apiSource(module)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	current := map[string]bool{}
+	for _, o := range g.ops {
+		current[o.name] = true
+		if pkg.methods[o.name] {
+			continue
+		}
+		name, err := add(snakeName(o.name), stubSource(o, module, pkg.receiver))
+		if err != nil {
+			return nil, nil, err
+		}
+		// Every stub comes with a test next to it, unless a file has its
+		// name already
+		if test := name + "_test"; !fileNames[test] {
+			formatted, err := format.Source([]byte(stubTestSource(o, module)))
+			if err != nil {
+				return nil, nil, fmt.Errorf("generated %s does not parse: %v", test, err)
+			}
+			fileNames[test] = true
+			files[path.Join(HandlersDir, test+".go")] = formatted
+		}
+	}
+
+	for name := range pkg.requests {
+		if !current[name] {
+			stale = append(stale, name)
+		}
+	}
+	sort.Strings(stale)
+	return files, stale, nil
+}
+
+// handlersPackage is what Stubs needs to know about the handlers package.
+type handlersPackage struct {
+	files    map[string]bool
+	declared bool            // whether it declares API
+	methods  map[string]bool // the methods of API
+	requests map[string]bool // the methods of API taking an openapi request
+	receiver string          // the receiver of the methods of API
+}
+
+func parseHandlers(fsys fs.FS) (*handlersPackage, error) {
+	pkg := &handlersPackage{
+		files:    map[string]bool{},
+		methods:  map[string]bool{},
+		requests: map[string]bool{},
+		receiver: "a *API",
+	}
+	entries, err := fs.ReadDir(fsys, HandlersDir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return pkg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", HandlersDir, err)
+	}
+
+	fset := token.NewFileSet()
+	receiverSet := false
+	for _, entry := range entries {
+		name := entry.Name()
+		pkg.files[name] = true
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		filePath := path.Join(HandlersDir, name)
+		data, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %v", filePath, err)
+		}
+		file, err := parser.ParseFile(fset, filePath, data, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", filePath, err)
+		}
+
+		for _, d := range file.Decls {
+			switch d := d.(type) {
+			case *ast.GenDecl:
+				for _, s := range d.Specs {
+					if ts, ok := s.(*ast.TypeSpec); ok && ts.Name.Name == handlersType {
+						pkg.declared = true
+					}
+				}
+			case *ast.FuncDecl:
+				recv, ok := apiReceiver(d)
+				if !ok {
+					continue
+				}
+				pkg.methods[d.Name.Name] = true
+				if takesRequest(d) {
+					pkg.requests[d.Name.Name] = true
+				}
+				if !receiverSet && recv != "" {
+					pkg.receiver, receiverSet = recv, true
+				}
+			}
+		}
+	}
+	return pkg, nil
+}
+
+// apiReceiver reports whether fn is a method of API and returns its
+// receiver as written, such as "a *API", or "" when it is unnamed.
+func apiReceiver(fn *ast.FuncDecl) (string, bool) {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 {
+		return "", false
+	}
+	field := fn.Recv.List[0]
+	typ, pointer := field.Type, false
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ, pointer = star.X, true
+	}
+	ident, ok := typ.(*ast.Ident)
+	if !ok || ident.Name != handlersType {
+		return "", false
+	}
+	if len(field.Names) == 0 || field.Names[0].Name == "_" {
+		return "", true
+	}
+	recv := field.Names[0].Name + " " + handlersType
+	if pointer {
+		recv = field.Names[0].Name + " *" + handlersType
+	}
+	return recv, true
+}
+
+// takesRequest reports whether a parameter of fn has a type of the openapi
+// package, as the handlers of operations do.
+func takesRequest(fn *ast.FuncDecl) bool {
+	for _, p := range fn.Type.Params.List {
+		sel, ok := p.Type.(*ast.SelectorExpr)
+		if !ok {
+			continue
+		}
+		if x, ok := sel.X.(*ast.Ident); ok && x.Name == "openapi" && strings.HasSuffix(sel.Sel.Name, "Request") {
+			return true
+		}
+	}
+	return false
+}
+
+func apiSource(module string) string {
+	return `package handlers
+
+import "` + module + `/internal/service"
+
+// API implements the operations of the OpenAPI spec. sova adds a stub
+// method to it for each operation it lacks; its methods reach the
+// application through services.
+type API struct {
+	services *service.Services
+}
+
+// NewAPI returns the handlers of the API on top of services.
+func NewAPI(services *service.Services) *API {
+	return &API{services: services}
+}
+`
+}
+
+func stubSource(o *operation, module, receiver string) string {
+	var c code
+	c.line("package handlers")
+	c.line("")
+	writeImports(&c, []string{"context", "net/http"}, module+"/internal/apperror", module+"/"+Dir)
+	c.comment(fmt.Sprintf("%s handles %s, %s.", o.name, o.OperationID, o.Operation))
+	c.line("func (%s) %s {", receiver, o.signature("openapi."))
+	notImplemented := fmt.Sprintf("apperror.New(http.StatusNotImplemented, %q)", o.OperationID+" is not implemented")
+	if o.result == "" {
+		c.line("return %s", notImplemented)
+	} else {
+		c.line("return resp, %s", notImplemented)
+	}
+	c.line("}")
+	return c.String()
+}
+
+// stubTestSource returns the test of the stub of o. It checks that the
+// stub answers 501, so it fails once the operation is implemented, as a
+// reminder to test the implementation instead.
+func stubTestSource(o *operation, module string) string {
+	var c code
+	c.line("package handlers")
+	c.line("")
+	writeImports(&c, []string{"context", "net/http", "testing"}, module+"/internal/apperror", module+"/"+Dir)
+	c.comment(fmt.Sprintf("Test%s checks the stub of %s. Replace it with tests of the\nimplementation: it fails once %s is implemented.", o.name, o.OperationID, o.OperationID))
+	c.line("func Test%s(t *testing.T) {", o.name)
+	if o.result == "" {
+		c.line("err := (&%s{}).%s(context.Background(), openapi.%s{})", handlersType, o.name, o.request)
+	} else {
+		c.line("_, err := (&%s{}).%s(context.Background(), openapi.%s{})", handlersType, o.name, o.request)
+	}
+	c.line("if !apperror.Is(err, http.StatusNotImplemented) {")
+	c.line("t.Errorf(\"Expected 501 Not Implemented, got %%v\", err)")
+	c.line("}")
+	c.line("}")
+	return c.String()
+}
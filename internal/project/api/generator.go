@@ -1,12 +1,21 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/openapi"
+	"github.com/go-sova/sova-cli/internal/project"
 	"github.com/go-sova/sova-cli/pkg/questions"
 	"github.com/go-sova/sova-cli/pkg/utils"
+	"github.com/go-sova/sova-cli/pkg/vfs"
 	"github.com/go-sova/sova-cli/templates"
 )
 
@@ -16,19 +25,38 @@ type APIProjectGenerator struct {
 	Answers        *questions.ProjectAnswers
 	templateLoader *templates.TemplateLoader
 	fileGenerator  *templates.FileGenerator
+	fs             vfs.FS
+	config         *config.Config
+	events         events.Sink
 	logger         *utils.Logger
+	ctx            context.Context
+	spec           *openapi.Spec
+	// generated holds the planned files that are not rendered from a
+	// template, such as the code generated from spec.
+	generated map[string][]byte
+}
+
+func init() {
+	project.Register("api", "A Go API project with a complete structure for API development", func(projectName, projectDir string, answers *questions.ProjectAnswers) project.Generator {
+		return NewAPIProjectGenerator(projectName, projectDir, answers)
+	})
 }
 
 func NewAPIProjectGenerator(projectName, projectDir string, answers *questions.ProjectAnswers) *APIProjectGenerator {
 	loader := templates.NewTemplateLoader()
-	return &APIProjectGenerator{
+	g := &APIProjectGenerator{
 		ProjectName:    projectName,
 		ProjectDir:     projectDir,
 		Answers:        answers,
 		templateLoader: loader,
 		fileGenerator:  templates.NewFileGenerator(loader),
-		logger:         utils.NewLoggerWithPrefix(utils.Info, "APIProjectGenerator"),
+		events:         events.NewTextSink(os.Stdout, os.Stderr),
+		logger:         utils.NewComponentLogger("APIProjectGenerator"),
+		ctx:            context.Background(),
 	}
+	g.SetFS(vfs.NewOSFS(projectDir))
+	g.SetConfig(config.Current())
+	return g
 }
 
 func (g *APIProjectGenerator) SetLogger(logger *utils.Logger) {
@@ -37,44 +65,150 @@ func (g *APIProjectGenerator) SetLogger(logger *utils.Logger) {
 	g.fileGenerator.SetLogger(logger)
 }
 
+// SetFS sets the filesystem the project is written to. Its root is the
+// project directory.
+func (g *APIProjectGenerator) SetFS(fsys vfs.FS) {
+	g.fs = fsys
+	g.fileGenerator.SetFS(fsys)
+}
+
+// SetConfig sets the configuration the project is generated with.
+func (g *APIProjectGenerator) SetConfig(cfg *config.Config) {
+	g.config = cfg
+	g.templateLoader.SetTemplateDir(cfg.Templates.Directory)
+}
+
+// SetOutput prints progress messages to w.
+func (g *APIProjectGenerator) SetOutput(w io.Writer) {
+	g.events = events.NewTextSink(w, w)
+}
+
+// SetEvents sets the sink progress events are emitted to.
+func (g *APIProjectGenerator) SetEvents(sink events.Sink) {
+	g.events = sink
+}
+
+// SetContext stops WriteFiles before the next file once ctx is done.
+func (g *APIProjectGenerator) SetContext(ctx context.Context) {
+	g.ctx = ctx
+}
+
+// SetOpenAPI generates the API from spec: its models, routes and docs in
+// internal/openapi, and stub handlers in internal/handlers.
+func (g *APIProjectGenerator) SetOpenAPI(spec *openapi.Spec) {
+	g.spec = spec
+}
+
+func (g *APIProjectGenerator) Events() events.Sink {
+	return g.events
+}
+
 func (g *APIProjectGenerator) Generate() (map[string]string, []string, error) {
+	logger := g.Answers.Values.String("Logger")
+	if logger == "" {
+		logger = "slog"
+	}
+
 	dirs := []string{
 		"cmd",
+		"internal/app",
 		"internal/server",
+		"internal/repository",
 		"internal/service",
 		"internal/handlers",
 		"internal/middleware",
 		"internal/routes",
+		"internal/logging",
+		"internal/health",
+		"internal/apperror",
+		"internal/testutil",
 	}
+	dirs = append(dirs, project.StructureDirs(g.config.Project.Structure)...)
 
 	fileTemplates := map[string]string{
-		"cmd/main.go":                   "api/main.tpl",
-		"internal/server/server.go":     "api/server.tpl",
-		"internal/routes/routes.go":     "api/routes.tpl",
-		"internal/service/service.go":   "api/service-init.tpl",
-		"internal/handlers/handlers.go": "api/handlers.tpl",
-		"internal/middleware/auth.go":   "api/middleware.tpl",
-		".env":                          "api/env.tpl",
-		"docker-compose.yml":            "api/docker-compose.tpl",
-		"Dockerfile":                    "api/dockerfile.tpl",
-		"go.mod":                        "api/go-mod.tpl",
-		".gitignore":                    "api/gitignore.tpl",
+		"cmd/main.go":                        "api/main.tpl",
+		"internal/server/server.go":          "api/server.tpl",
+		"internal/routes/routes.go":          "api/routes.tpl",
+		"internal/repository/repository.go":  "api/repository.tpl",
+		"internal/service/service.go":        "api/service.tpl",
+		"internal/handlers/handlers.go":      "api/handlers.tpl",
+		"internal/handlers/handlers_test.go": "api/handlers-test.tpl",
+		"internal/testutil/testutil.go":      "api/testutil.tpl",
+		"Makefile":                           "api/makefile.tpl",
+		".env":                               "api/env.tpl",
+		"docker-compose.yml":                 "api/docker-compose.tpl",
+		"Dockerfile":                         "api/dockerfile.tpl",
+		"go.mod":                             "api/go-mod.tpl",
+		".github/workflows/ci.yml":           "api/ci.tpl",
+		".gitignore":                         "api/gitignore.tpl",
+		"internal/middleware/logging.go":     "api/logging.tpl",
+		"internal/logging/logging.go":        "api/logging-" + logger + ".tpl",
+		"internal/health/health.go":          "api/health.tpl",
+		"internal/apperror/apperror.go":      "api/apperror.tpl",
+		"internal/apperror/binding.go":       "api/apperror-binding.tpl",
+		"internal/apperror/apperror_test.go": "api/apperror-test.tpl",
+		"internal/middleware/errors.go":      "api/middleware-errors.tpl",
+		"internal/middleware/errors_test.go": "api/middleware-errors-test.tpl",
+	}
+
+	// Every middleware of the Middleware question has a file and tests
+	for _, name := range g.Answers.Values.Strings("Middleware") {
+		fileTemplates["internal/middleware/"+name+".go"] = "api/middleware-" + name + ".tpl"
+		fileTemplates["internal/middleware/"+name+"_test.go"] = "api/middleware-" + name + "-test.tpl"
+	}
+
+	// The application container, built by hand, by Wire or by fx
+	switch g.Answers.Values.String("DI") {
+	case "fx":
+		fileTemplates["internal/app/app.go"] = "api/app-fx.tpl"
+	case "wire":
+		fileTemplates["internal/app/app.go"] = "api/app.tpl"
+		fileTemplates["internal/app/providers.go"] = "api/providers.tpl"
+		fileTemplates["internal/app/wire.go"] = "api/wire.tpl"
+		fileTemplates["internal/app/wire_gen.go"] = "api/wire-gen.tpl"
+	default:
+		fileTemplates["internal/app/app.go"] = "api/app.tpl"
+		fileTemplates["internal/app/providers.go"] = "api/providers.tpl"
+	}
+
+	if auth := g.Answers.Values.String("Auth"); auth != "" && auth != "none" {
+		fileTemplates["internal/middleware/auth.go"] = "api/auth.tpl"
 	}
 
-	if g.Answers.UseZap {
-		fileTemplates["internal/middleware/logging.go"] = "api/logging.tpl"
+	// Connections to the infrastructure go to internal/platform
+	platform := false
+	if g.Answers.Values.Bool("UsePostgres") {
+		fileTemplates["internal/platform/postgres.go"] = "api/postgres.tpl"
+		fileTemplates["internal/testutil/postgres.go"] = "api/testutil-postgres.tpl"
+		fileTemplates["internal/repository/repository_test.go"] = "api/repository-test.tpl"
+		platform = true
 	}
 
-	if g.Answers.UsePostgres {
-		fileTemplates["internal/service/postgres.go"] = "api/postgres.tpl"
+	if g.Answers.Values.Bool("UseRedis") {
+		fileTemplates["internal/platform/redis.go"] = "api/redis.tpl"
+		platform = true
 	}
 
-	if g.Answers.UseRedis {
-		fileTemplates["internal/service/redis.go"] = "api/redis.tpl"
+	if g.Answers.Values.Bool("UseRabbitMQ") {
+		fileTemplates["internal/platform/rabbitmq.go"] = "api/rabbitmq.tpl"
+		platform = true
 	}
 
-	if g.Answers.UseRabbitMQ {
-		fileTemplates["internal/service/rabbitmq.go"] = "api/rabbitmq.tpl"
+	if platform {
+		dirs = append(dirs, "internal/platform")
+	}
+
+	if g.Answers.Values.Bool("UseMetrics") {
+		dirs = append(dirs, "internal/metrics")
+		fileTemplates["internal/metrics/metrics.go"] = "api/metrics.tpl"
+		fileTemplates["internal/middleware/metrics.go"] = "api/metrics-middleware.tpl"
+		fileTemplates["prometheus.yml"] = "api/prometheus.tpl"
+	}
+
+	if g.Answers.Values.Bool("UseTracing") {
+		dirs = append(dirs, "internal/tracing")
+		fileTemplates["internal/tracing/tracing.go"] = "api/tracing.tpl"
+		fileTemplates["otel-collector.yaml"] = "api/otel-collector.tpl"
 	}
 
 	files := make(map[string]string)
@@ -82,35 +216,134 @@ func (g *APIProjectGenerator) Generate() (map[string]string, []string, error) {
 		files[filePath] = templateName
 	}
 
+	g.generated = nil
+	if g.spec != nil {
+		generated, err := g.openAPIFiles()
+		if err != nil {
+			return nil, nil, err
+		}
+		dirs = append(dirs, "api", openapi.Dir)
+		for filePath := range generated {
+			files[filePath] = ""
+		}
+		g.generated = generated
+	}
+
 	return files, dirs, nil
 }
 
-func (g *APIProjectGenerator) WriteFiles(files map[string]string) error {
-	for filePath, templateName := range files {
-		fullPath := filepath.Join(g.ProjectDir, filePath)
-
-		data := map[string]interface{}{
-			"ProjectName":        g.ProjectName,
-			"ProjectDescription": "A Go API with clean architecture",
-			"ModuleName":         g.ProjectName,
-			"GoVersion":          "1.21",
-			"UsePostgres":        g.Answers.UsePostgres,
-			"UseRedis":           g.Answers.UseRedis,
-			"UseRabbitMQ":        g.Answers.UseRabbitMQ,
-			"UseZap":             g.Answers.UseZap,
-		}
+// openAPIFiles returns the files generated from the spec: the openapi
+// package, a copy of the spec and stubs for the handlers.
+func (g *APIProjectGenerator) openAPIFiles() (map[string][]byte, error) {
+	files, err := openapi.Generate(g.spec, g.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+	files[g.spec.File()] = g.spec.Raw
+
+	stubs, _, err := openapi.Stubs(g.fs, g.spec, g.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+	for filePath, data := range stubs {
+		files[filePath] = data
+	}
+	return files, nil
+}
+
+// TemplateData returns the values every API template is rendered with.
+func (g *APIProjectGenerator) TemplateData() (map[string]interface{}, error) {
+	return project.TemplateData("api", g.Answers, g.config, map[string]interface{}{
+		"ProjectName":        g.ProjectName,
+		"ProjectDescription": "A Go API with clean architecture",
+		"ModuleName":         g.ProjectName,
+		"OpenAPI":            g.spec != nil,
+		"UseMiddleware":      g.middleware(),
+	})
+}
+
+// middleware returns the chosen middleware as a set, so templates can test
+// for one with {{if .UseMiddleware.cors}}.
+func (g *APIProjectGenerator) middleware() map[string]bool {
+	chosen := map[string]bool{}
+	for _, name := range g.Answers.Values.Strings("Middleware") {
+		chosen[name] = true
+	}
+	return chosen
+}
+
+// WriteDirs creates the planned directories, including the project root.
+func (g *APIProjectGenerator) WriteDirs(dirs []string) error {
+	if err := utils.CreateDirIfNotExistsIn(g.fs, "."); err != nil {
+		return fmt.Errorf("failed to create project directory: %v", err)
+	}
 
-		dir := filepath.Dir(fullPath)
-		if err := os.MkdirAll(dir, 0755); err != nil {
+	for _, dir := range dirs {
+		start := time.Now()
+		if err := utils.CreateDirIfNotExistsIn(g.fs, dir); err != nil {
 			return fmt.Errorf("failed to create directory %s: %v", dir, err)
 		}
+		g.events.Emit(events.Event{
+			Type:       events.DirCreated,
+			Path:       filepath.Join(g.ProjectDir, dir),
+			DurationMS: events.Milliseconds(start),
+		})
+	}
+
+	return nil
+}
 
-		if err := g.fileGenerator.GenerateFile(templateName, fullPath, data); err != nil {
+func (g *APIProjectGenerator) WriteFiles(files map[string]string) error {
+	data, err := g.TemplateData()
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	for _, filePath := range paths {
+		if err := g.ctx.Err(); err != nil {
+			return err
+		}
+
+		templateName := files[filePath]
+		start := time.Now()
+		if generated, ok := g.generated[filePath]; ok {
+			if err := g.fs.WriteFile(filePath, generated, 0644); err != nil {
+				return fmt.Errorf("failed to write file %s: %v", filePath, err)
+			}
+		} else if err := g.fileGenerator.GenerateFile(templateName, filePath, data); err != nil {
 			return fmt.Errorf("failed to generate file %s from template %s: %v", filePath, templateName, err)
 		}
 
-		fmt.Printf("Created file: %s\n", fullPath)
+		g.events.Emit(events.Event{
+			Type:       events.FileWritten,
+			Path:       filepath.Join(g.ProjectDir, filePath),
+			Template:   templateName,
+			DurationMS: events.Milliseconds(start),
+		})
+	}
+
+	start := time.Now()
+	manifest, err := project.NewManifest("api", g.Answers)
+	if err != nil {
+		return err
+	}
+	if g.spec != nil {
+		manifest.OpenAPI = g.spec.File()
+	}
+	if err := manifest.Write(g.fs); err != nil {
+		return err
 	}
+	g.events.Emit(events.Event{
+		Type:       events.FileWritten,
+		Path:       filepath.Join(g.ProjectDir, project.ManifestFile),
+		DurationMS: events.Milliseconds(start),
+	})
 
 	return nil
 }
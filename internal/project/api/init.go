@@ -2,9 +2,12 @@ package api
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/openapi"
+	"github.com/go-sova/sova-cli/internal/project"
 	"github.com/go-sova/sova-cli/pkg/questions"
 	"github.com/spf13/cobra"
 )
@@ -13,55 +16,121 @@ var InitCmd = &cobra.Command{
 	Use:   "api [project-name]",
 	Short: "Initialize a new Go API project",
 	Long: `Initialize a new Go API project with a clean architecture structure.
-This command will create a new directory with the project name and set up all necessary files and directories.`,
+This command will create a new directory with the project name and set up all necessary files and directories.
+Use --archive to produce a zip or tar.gz archive instead of writing to the working tree.
+Use --openapi to generate the routes, models and handler stubs of the API from an
+OpenAPI 3 spec; 'sova add openapi' regenerates them when the spec changes.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		projectName := args[0]
-		projectDir := filepath.Join(".", projectName)
-
-		if _, err := os.Stat(projectDir); !os.IsNotExist(err) {
-			return fmt.Errorf("directory %s already exists", projectDir)
+		outputFormat, _ := cmd.Flags().GetString("output")
+		format, err := events.ParseFormat(outputFormat)
+		if err != nil {
+			return err
 		}
+		archivePath, _ := cmd.Flags().GetString("archive")
+		sink := project.EventSink(format, archivePath)
 
-		if err := os.MkdirAll(projectDir, 0755); err != nil {
-			return fmt.Errorf("failed to create project directory: %v", err)
-		}
+		return project.Report(sink, args[0], run(cmd, args[0], format, sink))
+	},
+}
 
-		answers, err := questions.AskProjectQuestions("api")
-		if err != nil {
-			return fmt.Errorf("failed to get project configuration: %v", err)
-		}
+func init() {
+	InitCmd.Flags().String("openapi", "", "generate the API from this OpenAPI 3 spec (YAML or JSON)")
+}
 
-		answers.ProjectName = projectName
+func run(cmd *cobra.Command, projectName string, format events.Format, sink events.Sink) error {
+	start := time.Now()
+	projectDir := filepath.Join(".", projectName)
 
-		generator := NewAPIProjectGenerator(projectName, projectDir, answers)
+	goVersion, _ := cmd.Flags().GetString("go-version")
+	cfg, err := project.ConfigWithGoVersion("api", goVersion, sink)
+	if err != nil {
+		return err
+	}
 
-		files, dirs, err := generator.Generate()
-		if err != nil {
-			return fmt.Errorf("failed to generate project files: %v", err)
-		}
+	archivePath, _ := cmd.Flags().GetString("archive")
+	archiveFormat, _ := cmd.Flags().GetString("archive-format")
 
-		for _, dir := range dirs {
-			dirPath := filepath.Join(projectDir, dir)
-			if err := os.MkdirAll(dirPath, 0755); err != nil {
-				return fmt.Errorf("failed to create directory %s: %v", dir, err)
-			}
-			fmt.Printf("Created directory: %s\n", dirPath)
-		}
+	output, err := project.NewOutput(projectName, projectDir, archivePath, archiveFormat)
+	if err != nil {
+		return err
+	}
 
-		if err := generator.WriteFiles(files); err != nil {
-			return fmt.Errorf("failed to write files: %v", err)
+	// Check the spec before asking any questions
+	var spec *openapi.Spec
+	if specPath, _ := cmd.Flags().GetString("openapi"); specPath != "" {
+		if spec, err = openapi.LoadFile(specPath); err != nil {
+			return err
+		}
+		if _, err := openapi.Generate(spec, projectName); err != nil {
+			return fmt.Errorf("%s: %v", specPath, err)
 		}
+	}
 
-		fmt.Printf("\nProject %s created successfully!\n", projectName)
-		fmt.Println("\nNext steps:")
-		fmt.Printf("cd %s\n", projectName)
-		fmt.Println("go mod tidy")
-		fmt.Println("docker compose up -d")
-		fmt.Println("go run cmd/main.go")
-		fmt.Println("\nYour API will be available at http://localhost:8080")
-		fmt.Println("Test the ping endpoint: curl http://localhost:8080/api/ping")
+	presetName, _ := cmd.Flags().GetString("preset")
+	preset, err := project.PresetAnswers(presetName, "api")
+	if err != nil {
+		return err
+	}
 
-		return nil
-	},
+	askOpts := output.AskOptions()
+	if format == events.JSON {
+		askOpts = append(askOpts, questions.WithStderr())
+	}
+	answers, err := questions.AskMissingQuestions(project.TemplateFS(cfg), "api", preset, askOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to get project configuration: %v", err)
+	}
+
+	answers.ProjectName = projectName
+
+	generator := NewAPIProjectGenerator(projectName, projectDir, answers)
+	generator.SetConfig(cfg)
+	generator.SetFS(output.FS())
+	generator.SetEvents(sink)
+	if spec != nil {
+		generator.SetOpenAPI(spec)
+	}
+
+	if err := project.Run(generator); err != nil {
+		return fmt.Errorf("failed to generate project: %v", err)
+	}
+
+	if err := output.Close(); err != nil {
+		return err
+	}
+
+	done := events.Event{
+		Type:       events.Done,
+		Project:    projectName,
+		Path:       output.Describe(),
+		DurationMS: events.Milliseconds(start),
+	}
+	if output.IsArchive() {
+		done.Message = fmt.Sprintf("Project %s written to %s", projectName, output.Describe())
+	} else {
+		done.Message = fmt.Sprintf("Project %s created successfully!", projectName)
+		done.Steps = []string{
+			"cd " + projectName,
+			"go mod tidy",
+			"docker compose up -d",
+			"go run cmd/main.go",
+		}
+		done.Hints = []string{
+			"Your API will be available at http://localhost:8080",
+			"Test the ping endpoint: curl http://localhost:8080/api/ping",
+		}
+		if answers.Values.String("Auth") == "jwt-hmac" {
+			done.Hints = append(done.Hints, "Set AUTH_JWT_SECRET in .env before starting the server, e.g. to the output of openssl rand -base64 32")
+		}
+		if spec != nil {
+			done.Hints = append(done.Hints,
+				"Browse the API docs at http://localhost:8080/docs",
+				"Implement the operations of the spec in internal/handlers",
+			)
+		}
+	}
+	sink.Emit(done)
+
+	return nil
 }
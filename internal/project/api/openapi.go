@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/openapi"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+)
+
+// routesFile is the file of an API project that registers its routes.
+const routesFile = "internal/routes/routes.go"
+
+// AddOpenAPI generates the API of the project in dir from the OpenAPI spec
+// at specPath, or from the spec recorded in its manifest when specPath is
+// empty. The openapi package is regenerated; handlers are only added for
+// operations the project does not implement yet, so running it again after
+// the spec changes keeps the code written against it.
+func AddOpenAPI(dir, specPath string, sink events.Sink) error {
+	start := time.Now()
+	fsys := vfs.NewOSFS(dir)
+
+	manifest, _, err := project.ReadManifest(fsys)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("no %s found: run sova add in the root of a project sova generated", project.ManifestFile)
+	}
+	if err != nil {
+		return err
+	}
+	if manifest.Template != "api" {
+		return fmt.Errorf("OpenAPI specs can only be added to api projects, not to %s projects", manifest.Template)
+	}
+	if specPath == "" {
+		if manifest.OpenAPI == "" {
+			return fmt.Errorf("%s records no OpenAPI spec: name the spec to add", project.ManifestFile)
+		}
+		specPath = filepath.Join(dir, filepath.FromSlash(manifest.OpenAPI))
+	}
+
+	spec, err := openapi.LoadFile(specPath)
+	if err != nil {
+		return err
+	}
+	module, err := project.ModulePath(fsys)
+	if err != nil {
+		return err
+	}
+
+	files, err := openapi.Generate(spec, module)
+	if err != nil {
+		return fmt.Errorf("%s: %v", specPath, err)
+	}
+	files[spec.File()] = spec.Raw
+	stubs, stale, err := openapi.Stubs(fsys, spec, module)
+	if err != nil {
+		return err
+	}
+	for filePath, data := range stubs {
+		files[filePath] = data
+	}
+
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+	for _, filePath := range paths {
+		// Leave files the spec did not change alone
+		if old, err := fs.ReadFile(fsys, filePath); err == nil && bytes.Equal(old, files[filePath]) {
+			continue
+		}
+		fileStart := time.Now()
+		if err := fsys.MkdirAll(path.Dir(filePath), 0755); err != nil {
+			return fmt.Errorf("failed to create directory %s: %v", path.Dir(filePath), err)
+		}
+		if err := fsys.WriteFile(filePath, files[filePath], 0644); err != nil {
+			return fmt.Errorf("failed to write file %s: %v", filePath, err)
+		}
+		sink.Emit(events.Event{
+			Type:       events.FileWritten,
+			Path:       filepath.Join(dir, filePath),
+			DurationMS: events.Milliseconds(fileStart),
+		})
+	}
+
+	for _, method := range stale {
+		sink.Emit(events.Event{
+			Type:    events.Warning,
+			Message: fmt.Sprintf("API.%s in %s handles no operation of the spec any more: remove it and its tests or restore the operation", method, openapi.HandlersDir),
+		})
+	}
+	if routes, err := fs.ReadFile(fsys, routesFile); err == nil && !bytes.Contains(routes, []byte("openapi.RegisterRoutes")) {
+		sink.Emit(events.Event{
+			Type: events.Warning,
+			Message: fmt.Sprintf("%s does not register the operations of the spec: add\n"+
+				"\tAPI *handlers.API\nto Dependencies, build it in internal/app with handlers.NewAPI(services), import %q and add\n"+
+				"\topenapi.RegisterRoutes(router, deps.API)\n\topenapi.RegisterDocs(router)\nto SetupRoutes", routesFile, module+"/"+openapi.Dir),
+		})
+	}
+
+	manifest.OpenAPI = spec.File()
+	if err := manifest.Write(fsys); err != nil {
+		return err
+	}
+
+	sink.Emit(events.Event{
+		Type:       events.Done,
+		Project:    manifest.ProjectName,
+		Path:       dir,
+		DurationMS: events.Milliseconds(start),
+		Message:    fmt.Sprintf("Generated the API of %s from %s", manifest.ProjectName, specPath),
+		Hints: []string{
+			"Implement the operations of the spec in " + openapi.HandlersDir,
+			"Run 'sova add openapi' again after changing " + spec.File(),
+		},
+	})
+	return nil
+}
@@ -1,12 +1,20 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"time"
 
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/project"
 	"github.com/go-sova/sova-cli/pkg/questions"
 	"github.com/go-sova/sova-cli/pkg/utils"
+	"github.com/go-sova/sova-cli/pkg/vfs"
 	"github.com/go-sova/sova-cli/templates"
 )
 
@@ -16,19 +24,34 @@ type CLIProjectGenerator struct {
 	Answers        *questions.ProjectAnswers
 	templateLoader *templates.TemplateLoader
 	fileGenerator  *templates.FileGenerator
+	fs             vfs.FS
+	config         *config.Config
+	events         events.Sink
 	logger         *utils.Logger
+	ctx            context.Context
+}
+
+func init() {
+	project.Register("cli", "A command-line interface application with Cobra", func(projectName, projectDir string, answers *questions.ProjectAnswers) project.Generator {
+		return NewCLIProjectGenerator(projectName, projectDir, answers)
+	})
 }
 
 func NewCLIProjectGenerator(projectName, projectDir string, answers *questions.ProjectAnswers) *CLIProjectGenerator {
 	loader := templates.NewTemplateLoader()
-	return &CLIProjectGenerator{
+	g := &CLIProjectGenerator{
 		ProjectName:    projectName,
 		ProjectDir:     projectDir,
 		Answers:        answers,
 		templateLoader: loader,
 		fileGenerator:  templates.NewFileGenerator(loader),
-		logger:         utils.NewLoggerWithPrefix(utils.Info, "CLIProjectGenerator"),
+		events:         events.NewTextSink(os.Stdout, os.Stderr),
+		logger:         utils.NewComponentLogger("CLIProjectGenerator"),
+		ctx:            context.Background(),
 	}
+	g.SetFS(vfs.NewOSFS(projectDir))
+	g.SetConfig(config.Current())
+	return g
 }
 
 func (g *CLIProjectGenerator) SetLogger(logger *utils.Logger) {
@@ -37,26 +60,65 @@ func (g *CLIProjectGenerator) SetLogger(logger *utils.Logger) {
 	g.fileGenerator.SetLogger(logger)
 }
 
+// SetFS sets the filesystem the project is written to. Its root is the
+// project directory.
+func (g *CLIProjectGenerator) SetFS(fsys vfs.FS) {
+	g.fs = fsys
+	g.fileGenerator.SetFS(fsys)
+}
+
+// SetConfig sets the configuration the project is generated with.
+func (g *CLIProjectGenerator) SetConfig(cfg *config.Config) {
+	g.config = cfg
+	g.templateLoader.SetTemplateDir(cfg.Templates.Directory)
+}
+
+// SetOutput prints progress messages to w.
+func (g *CLIProjectGenerator) SetOutput(w io.Writer) {
+	g.events = events.NewTextSink(w, w)
+}
+
+// SetEvents sets the sink progress events are emitted to.
+func (g *CLIProjectGenerator) SetEvents(sink events.Sink) {
+	g.events = sink
+}
+
+// SetContext stops WriteFiles before the next file once ctx is done.
+func (g *CLIProjectGenerator) SetContext(ctx context.Context) {
+	g.ctx = ctx
+}
+
+func (g *CLIProjectGenerator) Events() events.Sink {
+	return g.events
+}
+
 func (g *CLIProjectGenerator) Generate() (map[string]string, []string, error) {
 	dirs := []string{
 		"cmd",
 		"internal",
 		"pkg",
-		"docs",
-		"scripts",
-		"test",
-		"cmd/root",
 		"internal/commands",
 		"internal/config",
+		"internal/utils",
 	}
+	dirs = append(dirs, project.StructureDirs(g.config.Project.Structure)...)
 
 	fileTemplates := map[string]string{
-		"cmd/root/root.go":          "cli/root.tpl",
-		"cmd/version/version.go":    "cli/version.tpl",
-		"internal/commands/cmd.go":  "cli/commands.tpl",
-		"internal/config/config.go": "cli/config.tpl",
-		"internal/utils/utils.go":   "cli/utils.tpl",
-		".gitignore":                "cli/gitignore.tpl",
+		"main.go":                       "cli/main.tpl",
+		"cmd/root.go":                   "cli/root.tpl",
+		"cmd/version.go":                "cli/version.tpl",
+		"internal/commands/commands.go": "cli/commands.tpl",
+		"internal/config/config.go":     "cli/config.tpl",
+		"internal/utils/utils.go":       "cli/utils.tpl",
+		"README.md":                     "cli/readme.tpl",
+		".gitignore":                    "cli/gitignore.tpl",
+		"go.mod":                        "cli/go-mod.tpl",
+		".github/workflows/ci.yml":      "cli/ci.tpl",
+	}
+
+	if logger := g.Answers.Values.String("Logger"); logger != "" && logger != "none" {
+		dirs = append(dirs, "internal/logging")
+		fileTemplates["internal/logging/logging.go"] = "cli/logging-" + logger + ".tpl"
 	}
 
 	files := make(map[string]string)
@@ -67,28 +129,76 @@ func (g *CLIProjectGenerator) Generate() (map[string]string, []string, error) {
 	return files, dirs, nil
 }
 
-func (g *CLIProjectGenerator) WriteFiles(files map[string]string) error {
-	for filePath, templateName := range files {
-		fullPath := filepath.Join(g.ProjectDir, filePath)
-
-		data := map[string]interface{}{
-			"ProjectName":        g.ProjectName,
-			"ProjectDescription": "A CLI application with clean architecture",
-			"ModuleName":         g.ProjectName,
-			"GoVersion":          "1.21",
-		}
+// TemplateData returns the values every CLI template is rendered with.
+func (g *CLIProjectGenerator) TemplateData() (map[string]interface{}, error) {
+	return project.TemplateData("cli", g.Answers, g.config, map[string]interface{}{
+		"ProjectName":        g.ProjectName,
+		"ProjectDescription": "A CLI application with clean architecture",
+		"ModuleName":         g.ProjectName,
+	})
+}
 
-		dir := filepath.Dir(fullPath)
-		if err := os.MkdirAll(dir, 0755); err != nil {
+// WriteDirs creates the planned directories, including the project root.
+func (g *CLIProjectGenerator) WriteDirs(dirs []string) error {
+	if err := utils.CreateDirIfNotExistsIn(g.fs, "."); err != nil {
+		return fmt.Errorf("failed to create project directory: %v", err)
+	}
+
+	for _, dir := range dirs {
+		start := time.Now()
+		if err := utils.CreateDirIfNotExistsIn(g.fs, dir); err != nil {
 			return fmt.Errorf("failed to create directory %s: %v", dir, err)
 		}
+		g.events.Emit(events.Event{
+			Type:       events.DirCreated,
+			Path:       filepath.Join(g.ProjectDir, dir),
+			DurationMS: events.Milliseconds(start),
+		})
+	}
 
-		if err := g.fileGenerator.GenerateFile(templateName, fullPath, data); err != nil {
+	return nil
+}
+
+func (g *CLIProjectGenerator) WriteFiles(files map[string]string) error {
+	data, err := g.TemplateData()
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(files))
+	for filePath := range files {
+		paths = append(paths, filePath)
+	}
+	sort.Strings(paths)
+
+	for _, filePath := range paths {
+		if err := g.ctx.Err(); err != nil {
+			return err
+		}
+
+		templateName := files[filePath]
+		start := time.Now()
+		if err := g.fileGenerator.GenerateFile(templateName, filePath, data); err != nil {
 			return fmt.Errorf("failed to generate file %s from template %s: %v", filePath, templateName, err)
 		}
 
-		fmt.Printf("Created file: %s\n", fullPath)
+		g.events.Emit(events.Event{
+			Type:       events.FileWritten,
+			Path:       filepath.Join(g.ProjectDir, filePath),
+			Template:   templateName,
+			DurationMS: events.Milliseconds(start),
+		})
+	}
+
+	start := time.Now()
+	if err := project.WriteManifest(g.fs, "cli", g.Answers); err != nil {
+		return err
 	}
+	g.events.Emit(events.Event{
+		Type:       events.FileWritten,
+		Path:       filepath.Join(g.ProjectDir, project.ManifestFile),
+		DurationMS: events.Milliseconds(start),
+	})
 
 	return nil
 }
@@ -2,9 +2,11 @@ package cli
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
+	"time"
 
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/project"
 	"github.com/go-sova/sova-cli/pkg/questions"
 	"github.com/spf13/cobra"
 )
@@ -13,59 +15,90 @@ var InitCmd = &cobra.Command{
 	Use:   "cli [project-name]",
 	Short: "Initialize a new Go CLI project",
 	Long: `Initialize a new Go CLI project with a clean architecture structure.
-This command will create a new directory with the project name and set up all necessary files and directories.`,
+This command will create a new directory with the project name and set up all necessary files and directories.
+Use --archive to produce a zip or tar.gz archive instead of writing to the working tree.`,
 	Args: cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		projectName := args[0]
-		projectDir := filepath.Join(".", projectName)
-
-		if _, err := os.Stat(projectDir); !os.IsNotExist(err) {
-			fmt.Printf("Error: directory %s already exists\n", projectDir)
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		outputFormat, _ := cmd.Flags().GetString("output")
+		format, err := events.ParseFormat(outputFormat)
+		if err != nil {
+			return err
 		}
+		archivePath, _ := cmd.Flags().GetString("archive")
+		sink := project.EventSink(format, archivePath)
 
-		if err := os.MkdirAll(projectDir, 0755); err != nil {
-			fmt.Printf("Error: failed to create project directory: %v\n", err)
-			return
-		}
+		return project.Report(sink, args[0], run(cmd, args[0], format, sink))
+	},
+}
 
-		answers, err := questions.AskProjectQuestions("cli")
-		if err != nil {
-			fmt.Printf("Error: failed to get project configuration: %v\n", err)
-			return
-		}
+func run(cmd *cobra.Command, projectName string, format events.Format, sink events.Sink) error {
+	start := time.Now()
+	projectDir := filepath.Join(".", projectName)
 
-		answers.ProjectName = projectName
+	goVersion, _ := cmd.Flags().GetString("go-version")
+	cfg, err := project.ConfigWithGoVersion("cli", goVersion, sink)
+	if err != nil {
+		return err
+	}
 
-		generator := NewCLIProjectGenerator(projectName, projectDir, answers)
+	archivePath, _ := cmd.Flags().GetString("archive")
+	archiveFormat, _ := cmd.Flags().GetString("archive-format")
 
-		files, dirs, err := generator.Generate()
-		if err != nil {
-			fmt.Printf("Error: failed to generate project files: %v\n", err)
-			return
-		}
+	output, err := project.NewOutput(projectName, projectDir, archivePath, archiveFormat)
+	if err != nil {
+		return err
+	}
 
-		for _, dir := range dirs {
-			dirPath := filepath.Join(projectDir, dir)
-			if err := os.MkdirAll(dirPath, 0755); err != nil {
-				fmt.Printf("Error: failed to create directory %s: %v\n", dir, err)
-				return
-			}
-			fmt.Printf("Created directory: %s\n", dirPath)
-		}
+	presetName, _ := cmd.Flags().GetString("preset")
+	preset, err := project.PresetAnswers(presetName, "cli")
+	if err != nil {
+		return err
+	}
+
+	askOpts := output.AskOptions()
+	if format == events.JSON {
+		askOpts = append(askOpts, questions.WithStderr())
+	}
+	answers, err := questions.AskMissingQuestions(project.TemplateFS(cfg), "cli", preset, askOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to get project configuration: %v", err)
+	}
+
+	answers.ProjectName = projectName
+
+	generator := NewCLIProjectGenerator(projectName, projectDir, answers)
+	generator.SetConfig(cfg)
+	generator.SetFS(output.FS())
+	generator.SetEvents(sink)
 
-		if err := generator.WriteFiles(files); err != nil {
-			fmt.Printf("Error: failed to write files: %v\n", err)
-			return
+	if err := project.Run(generator); err != nil {
+		return fmt.Errorf("failed to generate project: %v", err)
+	}
+
+	if err := output.Close(); err != nil {
+		return err
+	}
+
+	done := events.Event{
+		Type:       events.Done,
+		Project:    projectName,
+		Path:       output.Describe(),
+		DurationMS: events.Milliseconds(start),
+	}
+	if output.IsArchive() {
+		done.Message = fmt.Sprintf("Project %s written to %s", projectName, output.Describe())
+	} else {
+		done.Message = fmt.Sprintf("Project %s created successfully!", projectName)
+		done.Steps = []string{
+			"1. cd " + projectName,
+			"2. go mod tidy",
+			"3. go run . version",
 		}
+		done.Hints = []string{
+			"Add commands as files in cmd/ that register themselves on rootCmd.",
+		}
+	}
+	sink.Emit(done)
 
-		fmt.Printf("\nProject %s created successfully!\n", projectName)
-		fmt.Println("\nNext steps:")
-		fmt.Printf("1. cd %s\n", projectName)
-		fmt.Println("2. go mod tidy")
-		fmt.Println("3. go run main.go")
-		fmt.Println("\nTry your CLI commands:")
-		fmt.Printf("   ./%s command1\n", projectName)
-		fmt.Printf("   ./%s command2\n", projectName)
-	},
+	return nil
 }
@@ -2,12 +2,12 @@ package project
 
 import (
 	"fmt"
-	"os"
-	"path/filepath"
 	"time"
 
+	"github.com/go-sova/sova-cli/internal/config"
 	"github.com/go-sova/sova-cli/pkg/questions"
 	"github.com/go-sova/sova-cli/pkg/utils"
+	"github.com/go-sova/sova-cli/pkg/vfs"
 	"github.com/go-sova/sova-cli/templates"
 )
 
@@ -15,12 +15,13 @@ type ProjectCreator struct {
 	logger         *utils.Logger
 	templateLoader *templates.TemplateLoader
 	fileGenerator  *templates.FileGenerator
+	fs             vfs.FS
 }
 
 func NewProjectCreator() *ProjectCreator {
 	loader := templates.NewTemplateLoader()
 	return &ProjectCreator{
-		logger:         utils.NewLoggerWithPrefix(utils.Info, "ProjectCreator"),
+		logger:         utils.NewComponentLogger("ProjectCreator"),
 		templateLoader: loader,
 		fileGenerator:  templates.NewFileGenerator(loader),
 	}
@@ -32,11 +33,19 @@ func (c *ProjectCreator) SetLogger(logger *utils.Logger) {
 	c.fileGenerator.SetLogger(logger)
 }
 
+// SetFS makes CreateProject write into fsys instead of the project
+// directory on disk. The root of fsys becomes the project root.
+func (c *ProjectCreator) SetFS(fsys vfs.FS) {
+	c.fs = fsys
+}
+
 type ProjectData struct {
 	ProjectName        string
 	ProjectDescription string
 	ModuleName         string
 	GoVersion          string
+	GoToolchain        string
+	GoImageVersion     string
 	Author             string
 	License            string
 	Year               string
@@ -46,7 +55,12 @@ func (c *ProjectCreator) CreateProject(projectName, projectDir, templateName str
 	c.logger.Info("Creating project: %s in directory: %s", projectName, projectDir)
 	c.logger.Info("Using template: %s", templateName)
 
-	if utils.DirExists(projectDir) {
+	out := c.fs
+	if out == nil {
+		out = vfs.NewOSFS(projectDir)
+	}
+
+	if !vfs.IsEmpty(out) {
 		if !force {
 			return fmt.Errorf("directory already exists: %s", projectDir)
 		}
@@ -63,16 +77,19 @@ func (c *ProjectCreator) CreateProject(projectName, projectDir, templateName str
 		return err
 	}
 
-	dirs, files := structure.GetAbsolutePaths(projectDir)
-	for _, dir := range dirs {
+	if err := utils.CreateDirIfNotExistsIn(out, "."); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+	for _, dir := range structure.Directories {
 		c.logger.Debug("Creating directory: %s", dir)
-		if err := utils.CreateDirIfNotExists(dir); err != nil {
+		if err := utils.CreateDirIfNotExistsIn(out, dir); err != nil {
 			return fmt.Errorf("failed to create directory: %w", err)
 		}
 	}
 
 	// Loop through files and handle template category subdirectories
-	for filePath, templateName := range files {
+	c.fileGenerator.SetFS(out)
+	for filePath, templateName := range structure.Files {
 		c.logger.Debug("Generating file: %s from template: %s", filePath, templateName)
 		if err := c.fileGenerator.GenerateFile(templateName, filePath, projectData); err != nil {
 			return fmt.Errorf("failed to generate file: %w", err)
@@ -84,13 +101,17 @@ func (c *ProjectCreator) CreateProject(projectName, projectDir, templateName str
 }
 
 func (c *ProjectCreator) getProjectData(projectName, projectDescription string) (*ProjectData, error) {
+	cfg := config.Current()
+	goData := GoTemplateData(cfg.Defaults.GoVersion)
 	return &ProjectData{
 		ProjectName:        projectName,
 		ProjectDescription: projectDescription,
 		ModuleName:         projectName,
-		GoVersion:          "1.21",
-		Author:             "Meyank Singh",
-		License:            "MIT",
+		GoVersion:          goData["GoVersion"].(string),
+		GoToolchain:        goData["GoToolchain"].(string),
+		GoImageVersion:     goData["GoImageVersion"].(string),
+		Author:             cfg.Defaults.Author,
+		License:            cfg.Defaults.License,
 		Year:               fmt.Sprintf("%d", time.Now().Year()),
 	}, nil
 }
@@ -118,21 +139,16 @@ func CreateProject(projectName, projectDir string, answers *questions.ProjectAns
 		return fmt.Errorf("failed to get project structure: %v", err)
 	}
 
-	dirs, files := structure.GetAbsolutePaths(projectDir)
+	out := vfs.NewOSFS(projectDir)
 
-	for _, dir := range dirs {
-		if err := utils.CreateDirIfNotExists(dir); err != nil {
+	for _, dir := range structure.Directories {
+		if err := utils.CreateDirIfNotExistsIn(out, dir); err != nil {
 			return fmt.Errorf("failed to create directory %s: %v", dir, err)
 		}
 	}
 
-	for path, template := range files {
-		dir := filepath.Dir(path)
-		if err := os.MkdirAll(dir, 0755); err != nil {
-			return fmt.Errorf("failed to create directory %s: %v", dir, err)
-		}
-
-		if err := utils.CopyFile(template, path); err != nil {
+	for path, template := range structure.Files {
+		if err := utils.CopyFileTo(out, template, path); err != nil {
 			return fmt.Errorf("failed to copy file %s to %s: %v", template, path, err)
 		}
 	}
@@ -0,0 +1,32 @@
+package project
+
+import (
+	"io/fs"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/deps"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+// TemplateFS returns the built-in templates under the files of the
+// configured template directory, from which the questions and dependency
+// catalogs of templates are read.
+func TemplateFS(cfg *config.Config) fs.FS {
+	return templates.WithOverrides(cfg.Templates.Directory)
+}
+
+// DependencyCatalog returns the dependency catalog of a project type,
+// honouring a deps.yaml in the configured template directory.
+func DependencyCatalog(projectType string, cfg *config.Config) (*deps.Catalog, error) {
+	return deps.LoadCatalog(TemplateFS(cfg), projectType)
+}
+
+// Requirements returns the modules the go.mod of a project requires given
+// its answers and the configured dependency versions.
+func Requirements(projectType string, answers map[string]interface{}, cfg *config.Config) ([]deps.Requirement, error) {
+	catalog, err := DependencyCatalog(projectType, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return catalog.Requirements(answers, cfg.Dependencies)
+}
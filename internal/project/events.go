@@ -0,0 +1,34 @@
+package project
+
+import (
+	"os"
+
+	"github.com/go-sova/sova-cli/internal/events"
+)
+
+// EventSink returns where the progress events of a new project go. JSON
+// events are written to stdout, or to stderr when the archive of the
+// project is streamed to stdout. Text is printed to stdout; for archives
+// only warnings and the summary are printed, to stderr.
+func EventSink(format events.Format, archivePath string) events.Sink {
+	if format == events.JSON {
+		if archivePath == "-" {
+			return events.NewJSONSink(os.Stderr)
+		}
+		return events.NewJSONSink(os.Stdout)
+	}
+
+	if archivePath != "" {
+		return events.Only(events.NewTextSink(os.Stderr, os.Stderr), events.Warning, events.Done)
+	}
+	return events.NewTextSink(os.Stdout, os.Stderr)
+}
+
+// Report emits err, if any, as the error event of projectName and
+// returns it.
+func Report(sink events.Sink, projectName string, err error) error {
+	if err != nil {
+		sink.Emit(events.Event{Type: events.Error, Project: projectName, Message: err.Error()})
+	}
+	return err
+}
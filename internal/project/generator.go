@@ -0,0 +1,98 @@
+package project
+
+import (
+	"context"
+	"io"
+	"sort"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/pkg/utils"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+)
+
+// Generator plans and writes the files of one project type.
+type Generator interface {
+	Generate() (map[string]string, []string, error)
+	WriteDirs(dirs []string) error
+	// WriteFiles renders the planned files, then writes ManifestFile.
+	WriteFiles(files map[string]string) error
+	SetFS(fsys vfs.FS)
+	// SetOutput prints the progress of the generator to w as text.
+	SetOutput(w io.Writer)
+	SetEvents(sink events.Sink)
+	Events() events.Sink
+	// SetContext makes generation stop between files once ctx is done.
+	SetContext(ctx context.Context)
+	SetLogger(logger *utils.Logger)
+	SetConfig(cfg *config.Config)
+}
+
+// Run plans the project and writes its directories and files. The plan is
+// emitted to the events of gen before anything is written.
+func Run(gen Generator) error {
+	files, dirs, err := gen.Generate()
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	// WriteFiles writes the manifest after the files of the template.
+	paths = append(paths, ManifestFile)
+	gen.Events().Emit(events.Event{Type: events.Plan, Dirs: dirs, Files: paths})
+
+	if err := gen.WriteDirs(dirs); err != nil {
+		return err
+	}
+
+	return gen.WriteFiles(files)
+}
+
+// TemplateData merges the answers of a project of projectType, the values derived from
+// its configuration and the values its generator provides, in increasing
+// order of precedence. Answers are also available as a whole under
+// "Answers" and the modules go.mod requires under "Requires".
+func TemplateData(projectType string, answers *questions.ProjectAnswers, cfg *config.Config, values map[string]interface{}) (map[string]interface{}, error) {
+	data := map[string]interface{}{"Answers": answers.Values}
+	for name, value := range answers.Values {
+		data[name] = value
+	}
+
+	requires, err := Requirements(projectType, answers.Values, cfg)
+	if err != nil {
+		return nil, err
+	}
+	data["Requires"] = requires
+
+	data["Author"] = cfg.Defaults.Author
+	data["License"] = cfg.Defaults.License
+	for name, value := range GoTemplateData(cfg.Defaults.GoVersion) {
+		data[name] = value
+	}
+
+	for name, value := range values {
+		data[name] = value
+	}
+	return data, nil
+}
+
+// StructureDirs returns the optional directories enabled by the
+// project.structure settings.
+func StructureDirs(s config.Structure) []string {
+	var dirs []string
+	if s.EnableDocs {
+		dirs = append(dirs, "docs")
+	}
+	if s.EnableScripts {
+		dirs = append(dirs, "scripts")
+	}
+	if s.EnableTests {
+		dirs = append(dirs, "test")
+	}
+	return dirs
+}
@@ -0,0 +1,80 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/goversion"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+// GoTemplateData returns the template values describing the Go release a
+// project targets: GoVersion for the go directive of go.mod, GoToolchain
+// for its optional toolchain line and GoImageVersion for golang Docker
+// images and CI. An empty or invalid version selects the local toolchain,
+// then goversion.Fallback.
+func GoTemplateData(version string) map[string]interface{} {
+	v, err := goversion.Resolve(version)
+	if err != nil && version != "" {
+		v, _ = goversion.Resolve("")
+	}
+
+	return map[string]interface{}{
+		"GoVersion":      v.Directive(),
+		"GoToolchain":    v.Toolchain(),
+		"GoImageVersion": v.Language(),
+	}
+}
+
+// ResolveGoVersion picks the Go release a new project of projectType
+// targets: requested, else the configured default, else the local
+// toolchain. The returned warnings describe a failed detection and
+// releases older than the template supports.
+func ResolveGoVersion(projectType, requested string) (goversion.Version, []string, error) {
+	if requested == "" {
+		requested = config.Current().Defaults.GoVersion
+	}
+
+	var warnings []string
+	v, err := goversion.Resolve(requested)
+	if err != nil {
+		if requested != "" {
+			return v, nil, err
+		}
+		warnings = append(warnings, fmt.Sprintf("%v; targeting Go %s", err, v.Language()))
+	}
+
+	info, err := templates.LoadInfo(projectType)
+	if err != nil {
+		return v, warnings, err
+	}
+	if info.MinGoVersion != "" {
+		min, err := goversion.Parse(info.MinGoVersion)
+		if err != nil {
+			return v, warnings, fmt.Errorf("template %s declares an invalid minGoVersion: %v", projectType, err)
+		}
+		if v.Less(min) {
+			warnings = append(warnings, fmt.Sprintf("Go %s is older than Go %s, which the %s template needs; the generated project may not build", v.Language(), min.Language(), projectType))
+		}
+	}
+
+	return v, warnings, nil
+}
+
+// ConfigWithGoVersion returns a copy of the current configuration that
+// targets the Go release chosen by ResolveGoVersion. Warnings are emitted
+// to sink.
+func ConfigWithGoVersion(projectType, requested string, sink events.Sink) (*config.Config, error) {
+	v, warnings, err := ResolveGoVersion(projectType, requested)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		sink.Emit(events.Event{Type: events.Warning, Message: warning})
+	}
+
+	cfg := *config.Current()
+	cfg.Defaults.GoVersion = v.String()
+	return &cfg, nil
+}
@@ -0,0 +1,94 @@
+package project
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+// ManifestFile is the file, relative to a generated project, that records
+// how the project was generated. Every generator writes it in WriteFiles.
+const ManifestFile = ".sova-project.yaml"
+
+// Manifest records the template and answers a project was generated from,
+// so sova commands run inside the project know what it should contain.
+type Manifest struct {
+	Template        string                 `yaml:"template"`
+	TemplateVersion string                 `yaml:"templateVersion,omitempty"`
+	ProjectName     string                 `yaml:"name"`
+	Answers         map[string]interface{} `yaml:"answers,omitempty"`
+	// OpenAPI is the project-relative path of the OpenAPI spec the API is
+	// generated from, if any.
+	OpenAPI string `yaml:"openapi,omitempty"`
+}
+
+// NewManifest returns the manifest of a project of projectType.
+func NewManifest(projectType string, answers *questions.ProjectAnswers) (*Manifest, error) {
+	info, err := templates.LoadInfo(projectType)
+	if err != nil {
+		return nil, err
+	}
+	return &Manifest{
+		Template:        projectType,
+		TemplateVersion: info.Version,
+		ProjectName:     answers.ProjectName,
+		Answers:         answers.Values,
+	}, nil
+}
+
+// WriteManifest writes the manifest of a project of projectType to the
+// root of fsys.
+func WriteManifest(fsys vfs.FS, projectType string, answers *questions.ProjectAnswers) error {
+	m, err := NewManifest(projectType, answers)
+	if err != nil {
+		return err
+	}
+	return m.Write(fsys)
+}
+
+// Write writes m to the root of fsys.
+func (m *Manifest) Write(fsys vfs.FS) error {
+	var buf bytes.Buffer
+	buf.WriteString("# Written by sova. Commands such as sova doctor read it; keep it in version control.\n")
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(m); err != nil {
+		return fmt.Errorf("failed to encode project manifest: %v", err)
+	}
+
+	if err := fsys.WriteFile(ManifestFile, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", ManifestFile, err)
+	}
+	return nil
+}
+
+// ReadManifest reads the manifest at the root of fsys and resolves its
+// answers against the current questions of its template.
+func ReadManifest(fsys fs.FS) (*Manifest, *questions.ProjectAnswers, error) {
+	data, err := fs.ReadFile(fsys, ManifestFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", ManifestFile, err)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse %s: %v", ManifestFile, err)
+	}
+	if _, ok := registry[m.Template]; !ok {
+		return nil, nil, fmt.Errorf("%s names unknown template: %s", ManifestFile, m.Template)
+	}
+
+	answers, err := questions.ResolveAnswers(TemplateFS(config.Current()), m.Template, migrateAnswers(m.Answers))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", ManifestFile, err)
+	}
+	answers.ProjectName = m.ProjectName
+	return &m, answers, nil
+}
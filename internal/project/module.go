@@ -0,0 +1,24 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// ModulePath returns the module path declared by the go.mod at the root of
+// fsys.
+func ModulePath(fsys fs.FS) (string, error) {
+	data, err := fs.ReadFile(fsys, "go.mod")
+	if err != nil {
+		return "", fmt.Errorf("failed to read go.mod: %v", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`), nil
+		}
+	}
+	return "", errors.New("go.mod declares no module")
+}
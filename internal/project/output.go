@@ -0,0 +1,120 @@
+package project
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/go-sova/sova-cli/internal/archive"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+)
+
+// Output is where a generated project ends up: a directory on disk, or an
+// archive assembled in memory.
+type Output struct {
+	ProjectName string
+	ProjectDir  string
+	ArchivePath string
+	Format      archive.Format
+	fs          vfs.FS
+}
+
+// NewOutput prepares the destination of a project. With an empty
+// archivePath the project is written to projectDir, which must not exist
+// or be empty. Otherwise it is packed into archivePath, or stdout for "-".
+// An empty format is inferred from archivePath.
+func NewOutput(projectName, projectDir, archivePath, format string) (*Output, error) {
+	o := &Output{
+		ProjectName: projectName,
+		ProjectDir:  projectDir,
+		ArchivePath: archivePath,
+	}
+
+	if archivePath == "" {
+		target := vfs.NewOSFS(projectDir)
+		if !vfs.IsEmpty(target) {
+			return nil, fmt.Errorf("directory %s already exists", projectDir)
+		}
+		o.fs = target
+		return o, nil
+	}
+
+	var err error
+	switch {
+	case format != "":
+		o.Format, err = archive.ParseFormat(format)
+	case archivePath == "-":
+		o.Format = archive.TarGz
+	default:
+		o.Format, err = archive.FormatFromPath(archivePath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	o.fs = vfs.NewMemFS()
+	return o, nil
+}
+
+// FS returns the filesystem generators should write to.
+func (o *Output) FS() vfs.FS {
+	return o.fs
+}
+
+func (o *Output) IsArchive() bool {
+	return o.ArchivePath != ""
+}
+
+// ToStdout reports whether the project is streamed to stdout, in which case
+// all human-readable output has to go elsewhere.
+func (o *Output) ToStdout() bool {
+	return o.ArchivePath == "-"
+}
+
+// Close writes the archive, if any. A partially written archive file is
+// removed on failure.
+func (o *Output) Close() error {
+	if !o.IsArchive() {
+		return nil
+	}
+
+	if o.ToStdout() {
+		return archive.Write(os.Stdout, o.Format, o.fs, o.ProjectName)
+	}
+
+	file, err := os.Create(o.ArchivePath)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+
+	err = archive.Write(file, o.Format, o.fs, o.ProjectName)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(o.ArchivePath)
+		return err
+	}
+
+	return nil
+}
+
+// Describe returns a short human-readable description of the destination.
+func (o *Output) Describe() string {
+	switch {
+	case o.ToStdout():
+		return "stdout (" + string(o.Format) + ")"
+	case o.IsArchive():
+		return o.ArchivePath
+	}
+	return o.ProjectDir
+}
+
+// AskOptions returns the prompt options matching the destination.
+func (o *Output) AskOptions() []survey.AskOpt {
+	if o.ToStdout() {
+		return []survey.AskOpt{questions.WithStderr()}
+	}
+	return nil
+}
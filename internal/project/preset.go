@@ -0,0 +1,71 @@
+package project
+
+import (
+	"fmt"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+// LoadPreset returns the preset called name from the configured config
+// file. It fails when the preset's template is unknown or the preset is
+// pinned to a template version other than the installed one.
+func LoadPreset(name string) (*config.Preset, error) {
+	preset, err := config.FindPreset(config.Current().File, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := registry[preset.Template]; !ok {
+		return nil, fmt.Errorf("preset %s uses unknown template: %s", name, preset.Template)
+	}
+
+	if preset.TemplateVersion != "" {
+		info, err := templates.LoadInfo(preset.Template)
+		if err != nil {
+			return nil, err
+		}
+		if info.Version != preset.TemplateVersion {
+			return nil, fmt.Errorf("preset %s is pinned to %s template version %s, but version %s is installed", name, preset.Template, preset.TemplateVersion, info.Version)
+		}
+	}
+
+	return preset, nil
+}
+
+// PresetAnswers returns the answers of the preset called name for a
+// project of type projectType, or nil when name is empty.
+func PresetAnswers(name, projectType string) (map[string]interface{}, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	preset, err := LoadPreset(name)
+	if err != nil {
+		return nil, err
+	}
+	if preset.Template != projectType {
+		return nil, fmt.Errorf("preset %s is for %s projects, not %s", name, preset.Template, projectType)
+	}
+	return migrateAnswers(preset.Answers), nil
+}
+
+// migrateAnswers rewrites answers to questions templates have since
+// replaced, so presets and manifests written for older template versions
+// keep resolving. UseZap became the Logger select in template version 2.0.0.
+func migrateAnswers(answers map[string]interface{}) map[string]interface{} {
+	useZap, ok := answers["UseZap"].(bool)
+	if !ok {
+		return answers
+	}
+
+	migrated := make(map[string]interface{}, len(answers))
+	for name, value := range answers {
+		migrated[name] = value
+	}
+	delete(migrated, "UseZap")
+	if _, ok := migrated["Logger"]; !ok && useZap {
+		migrated["Logger"] = "zap"
+	}
+	return migrated
+}
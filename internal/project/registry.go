@@ -0,0 +1,55 @@
+package project
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-sova/sova-cli/pkg/questions"
+)
+
+// Factory creates the generator of a project type.
+type Factory func(projectName, projectDir string, answers *questions.ProjectAnswers) Generator
+
+type registeredType struct {
+	description string
+	factory     Factory
+}
+
+var registry = map[string]registeredType{}
+
+// Register makes a project type available to sova init, sova serve and
+// the template manager. Generator packages call it from their init
+// functions.
+func Register(projectType, description string, factory Factory) {
+	if _, exists := registry[projectType]; exists {
+		panic("project type registered twice: " + projectType)
+	}
+	registry[projectType] = registeredType{description: description, factory: factory}
+}
+
+// ProjectTypes returns the registered project types in alphabetical order.
+func ProjectTypes() []string {
+	types := make([]string, 0, len(registry))
+	for projectType := range registry {
+		types = append(types, projectType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// NewGenerator returns the generator for a registered project type.
+func NewGenerator(projectType, projectName, projectDir string, answers *questions.ProjectAnswers) (Generator, error) {
+	t, ok := registry[projectType]
+	if !ok {
+		return nil, fmt.Errorf("unknown template: %s", projectType)
+	}
+	return t.factory(projectName, projectDir, answers), nil
+}
+
+func describe(projectType string) (string, error) {
+	t, ok := registry[projectType]
+	if !ok {
+		return "", fmt.Errorf("unknown template: %s", projectType)
+	}
+	return t.description, nil
+}
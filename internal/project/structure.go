@@ -28,21 +28,27 @@ func APIProjectStructure(projectName string) *ProjectStructure {
 			"internal/middleware",
 			"internal/models",
 			"internal/server",
+			"internal/repository",
 			"internal/service",
+			"internal/platform",
+			"internal/app",
 			"internal/routes",
 		},
 		Files: map[string]string{
 			"cmd/main.go":                       "api/main.tpl",
 			"internal/config/config.go":         "api/config.tpl",
 			"internal/handlers/handlers.go":     "api/handlers.tpl",
-			"internal/middleware/middleware.go": "api/middleware.tpl",
+			"internal/middleware/auth.go":       "api/auth.tpl",
 			"internal/models/models.go":         "api/models.tpl",
 			"internal/server/server.go":         "api/server.tpl",
 			"internal/routes/routes.go":         "api/routes.tpl",
-			"internal/service/service.go":       "api/service-init.tpl",
-			"internal/service/postgres.go":      "api/postgres.tpl",
-			"internal/service/redis.go":         "api/redis.tpl",
-			"internal/service/rabbitmq.go":      "api/rabbitmq.tpl",
+			"internal/app/app.go":               "api/app.tpl",
+			"internal/app/providers.go":         "api/providers.tpl",
+			"internal/repository/repository.go": "api/repository.tpl",
+			"internal/service/service.go":       "api/service.tpl",
+			"internal/platform/postgres.go":     "api/postgres.tpl",
+			"internal/platform/redis.go":        "api/redis.tpl",
+			"internal/platform/rabbitmq.go":     "api/rabbitmq.tpl",
 			"internal/middleware/logging.go":    "api/logging.tpl",
 			".env":                              "api/env.tpl",
 			"docker-compose.yml":                "api/docker-compose.tpl",
@@ -1,8 +1,6 @@
 package project
 
 import (
-	"fmt"
-
 	"github.com/go-sova/sova-cli/pkg/utils"
 	"github.com/go-sova/sova-cli/templates"
 )
@@ -15,7 +13,7 @@ type TemplateManager struct {
 func NewTemplateManager() *TemplateManager {
 	loader := templates.NewTemplateLoader()
 	return &TemplateManager{
-		logger:         utils.NewLoggerWithPrefix(utils.Info, "TemplateManager"),
+		logger:         utils.NewComponentLogger("TemplateManager"),
 		templateLoader: loader,
 	}
 }
@@ -27,29 +25,16 @@ func (m *TemplateManager) SetLogger(logger *utils.Logger) {
 
 func (m *TemplateManager) ListTemplates() ([]string, error) {
 	m.logger.Debug("Listing templates")
-	return []string{"api", "cli"}, nil
+	return ProjectTypes(), nil
 }
 
 func (m *TemplateManager) GetTemplateDescription(templateName string) (string, error) {
 	m.logger.Debug("Getting description for template: %s", templateName)
-
-	switch templateName {
-	case "api":
-		return "A Go API project with a complete structure for API development", nil
-	case "cli":
-		return "A command-line interface application with Cobra", nil
-	}
-
-	return "", fmt.Errorf("unknown template: %s", templateName)
+	return describe(templateName)
 }
 
 func (m *TemplateManager) ValidateTemplate(templateName string) error {
 	m.logger.Debug("Validating template: %s", templateName)
-
-	switch templateName {
-	case "api", "cli":
-		return nil
-	}
-
-	return fmt.Errorf("unknown template: %s", templateName)
+	_, err := describe(templateName)
+	return err
 }
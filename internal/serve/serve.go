@@ -0,0 +1,306 @@
+// Package serve exposes project generation over HTTP. Every request is
+// generated into its own in-memory filesystem with the same generators
+// sova init uses, and returned as an archive.
+package serve
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-sova/sova-cli/internal/archive"
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/pkg/utils"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+const (
+	DefaultMaxRequestBytes = 64 << 10
+	DefaultMaxProjectBytes = 16 << 20
+	DefaultTimeout         = 30 * time.Second
+)
+
+// projectNamePattern restricts project names to values that are safe as a
+// directory, archive folder and Go module path.
+var projectNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]{0,63}$`)
+
+// Options configures the handler. Zero values select the defaults.
+type Options struct {
+	// MaxRequestBytes limits the size of request bodies.
+	MaxRequestBytes int64
+	// MaxProjectBytes limits the total size of the generated files.
+	MaxProjectBytes int64
+	// Timeout bounds the time spent on a single request.
+	Timeout time.Duration
+	Logger  *utils.Logger
+}
+
+// GenerateRequest is the body accepted by the generate endpoint.
+type GenerateRequest struct {
+	ProjectName string                 `json:"project_name"`
+	Format      string                 `json:"format,omitempty"`
+	Answers     map[string]interface{} `json:"answers,omitempty"`
+}
+
+// TemplateInfo describes a project type and the answers it accepts.
+type TemplateInfo struct {
+	Name        string                 `json:"name"`
+	Version     string                 `json:"version"`
+	Description string                 `json:"description"`
+	Schema      map[string]interface{} `json:"schema"`
+}
+
+type handler struct {
+	opts      Options
+	templates *project.TemplateManager
+}
+
+// NewHandler returns the HTTP API:
+//
+//	GET  /healthz
+//	GET  /v1/templates
+//	GET  /v1/templates/{name}
+//	POST /v1/templates/{name}/generate
+func NewHandler(opts Options) http.Handler {
+	if opts.MaxRequestBytes <= 0 {
+		opts.MaxRequestBytes = DefaultMaxRequestBytes
+	}
+	if opts.MaxProjectBytes <= 0 {
+		opts.MaxProjectBytes = DefaultMaxProjectBytes
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Logger == nil {
+		opts.Logger = utils.NewComponentLogger("Serve")
+	}
+
+	h := &handler{
+		opts:      opts,
+		templates: project.NewTemplateManager(),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", h.health)
+	mux.HandleFunc("/v1/templates", h.listTemplates)
+	mux.HandleFunc("/v1/templates/", h.template)
+
+	timeoutBody, _ := json.Marshal(errorBody{Error: "request timed out"})
+	return h.logRequests(http.TimeoutHandler(mux, opts.Timeout, string(timeoutBody)))
+}
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, args ...interface{}) {
+	writeJSON(w, status, errorBody{Error: fmt.Sprintf(format, args...)})
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method == method {
+		return true
+	}
+	w.Header().Set("Allow", method)
+	writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	return false
+}
+
+func (h *handler) health(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (h *handler) templateInfo(name string) (*TemplateInfo, error) {
+	description, err := h.templates.GetTemplateDescription(name)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := questions.JSONSchema(project.TemplateFS(config.Current()), name)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := templates.LoadInfo(name)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateInfo{Name: name, Version: meta.Version, Description: description, Schema: schema}, nil
+}
+
+func (h *handler) listTemplates(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+
+	names, err := h.templates.ListTemplates()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	infos := make([]*TemplateInfo, 0, len(names))
+	for _, name := range names {
+		info, err := h.templateInfo(name)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "%v", err)
+			return
+		}
+		infos = append(infos, info)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{"templates": infos})
+}
+
+// template serves /v1/templates/{name} and /v1/templates/{name}/generate.
+func (h *handler) template(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, "/v1/templates/")
+	name, action, _ := strings.Cut(rest, "/")
+
+	if h.templates.ValidateTemplate(name) != nil {
+		writeError(w, http.StatusNotFound, "unknown template: %s", name)
+		return
+	}
+
+	switch action {
+	case "":
+		if !allowMethod(w, r, http.MethodGet) {
+			return
+		}
+		info, err := h.templateInfo(name)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "%v", err)
+			return
+		}
+		writeJSON(w, http.StatusOK, info)
+	case "generate":
+		if !allowMethod(w, r, http.MethodPost) {
+			return
+		}
+		h.generate(w, r, name)
+	default:
+		writeError(w, http.StatusNotFound, "not found: %s", r.URL.Path)
+	}
+}
+
+func (h *handler) generate(w http.ResponseWriter, r *http.Request, projectType string) {
+	req, status, err := h.decodeRequest(w, r)
+	if err != nil {
+		writeError(w, status, "%v", err)
+		return
+	}
+
+	format := archive.TarGz
+	if req.Format != "" {
+		if format, err = archive.ParseFormat(req.Format); err != nil {
+			writeError(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+	}
+
+	answers, err := questions.ResolveAnswers(project.TemplateFS(config.Current()), projectType, req.Answers)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+	answers.ProjectName = req.ProjectName
+
+	// Each request renders into its own filesystem with its own generator,
+	// so concurrent requests share no mutable state.
+	out := vfs.NewMemFS()
+	limited := vfs.NewLimitFS(out, h.opts.MaxProjectBytes)
+	generator, err := project.NewGenerator(projectType, req.ProjectName, req.ProjectName, answers)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "%v", err)
+		return
+	}
+	generator.SetFS(limited)
+	generator.SetEvents(events.Discard)
+	generator.SetLogger(h.opts.Logger)
+	// The timeout handler answers a request whose context ends, so stop
+	// rendering for a client that no longer waits for the project.
+	generator.SetContext(r.Context())
+
+	if err := project.Run(generator); err != nil {
+		switch {
+		case r.Context().Err() != nil:
+		case limited.Exceeded():
+			writeError(w, http.StatusRequestEntityTooLarge, "generated project exceeds %d bytes", h.opts.MaxProjectBytes)
+		default:
+			writeError(w, http.StatusInternalServerError, "failed to generate project: %v", err)
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := archive.Write(&buf, format, out, req.ProjectName); err != nil {
+		writeError(w, http.StatusInternalServerError, "%v", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, req.ProjectName, format))
+	w.WriteHeader(http.StatusOK)
+	w.Write(buf.Bytes())
+}
+
+// decodeRequest reads and validates a generate request. The returned status
+// is meaningful only when err is not nil.
+func (h *handler) decodeRequest(w http.ResponseWriter, r *http.Request) (*GenerateRequest, int, error) {
+	body := http.MaxBytesReader(w, r.Body, h.opts.MaxRequestBytes)
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	var req GenerateRequest
+	if err := decoder.Decode(&req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return nil, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", h.opts.MaxRequestBytes)
+		}
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err)
+	}
+	if decoder.More() {
+		return nil, http.StatusBadRequest, fmt.Errorf("invalid request body: unexpected data after JSON object")
+	}
+
+	if !projectNamePattern.MatchString(req.ProjectName) {
+		return nil, http.StatusBadRequest, fmt.Errorf("project_name must start with a letter and contain only letters, digits, '.', '_' or '-' (at most 64 characters)")
+	}
+
+	return &req, 0, nil
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (h *handler) logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		h.opts.Logger.Info("%s %s %d %s", r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
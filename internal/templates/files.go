@@ -3,22 +3,24 @@ package templates
 import (
 	"bytes"
 	"fmt"
-	"os"
-	"path/filepath"
+	"path"
 	"text/template"
 
 	"github.com/go-sova/sova-cli/pkg/utils"
+	"github.com/go-sova/sova-cli/pkg/vfs"
 )
 
 type FileGenerator struct {
 	loader *TemplateLoader
+	fs     vfs.FS
 	logger *utils.Logger
 }
 
 func NewFileGenerator(loader *TemplateLoader) *FileGenerator {
 	return &FileGenerator{
 		loader: loader,
-		logger: utils.NewLoggerWithPrefix(utils.Info, "FileGenerator"),
+		fs:     vfs.NewOSFS("."),
+		logger: utils.NewComponentLogger("FileGenerator"),
 	}
 }
 
@@ -26,10 +28,14 @@ func (g *FileGenerator) SetLogger(logger *utils.Logger) {
 	g.logger = logger
 }
 
+func (g *FileGenerator) SetFS(fsys vfs.FS) {
+	g.fs = fsys
+}
+
 func (g *FileGenerator) GenerateFile(templateName, outputPath string, data interface{}) error {
 	g.logger.Debug("Generating file from template: %s -> %s", templateName, outputPath)
 
-	if utils.FileExists(outputPath) {
+	if vfs.Exists(g.fs, outputPath) {
 		g.logger.Warning("Output file already exists: %s", outputPath)
 	}
 
@@ -43,12 +49,12 @@ func (g *FileGenerator) GenerateFile(templateName, outputPath string, data inter
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	dir := filepath.Dir(outputPath)
-	if err := utils.CreateDirIfNotExists(dir); err != nil {
+	dir := path.Dir(outputPath)
+	if err := g.fs.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 	
-	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+	if err := g.fs.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 	
@@ -59,7 +65,7 @@ func (g *FileGenerator) GenerateFile(templateName, outputPath string, data inter
 func (g *FileGenerator) GenerateFileWithFuncs(templateName, outputPath string, data interface{}, funcs template.FuncMap) error {
 	g.logger.Debug("Generating file from template with funcs: %s -> %s", templateName, outputPath)
 	
-	if utils.FileExists(outputPath) {
+	if vfs.Exists(g.fs, outputPath) {
 		g.logger.Warning("Output file already exists: %s", outputPath)
 	}
 
@@ -73,12 +79,12 @@ func (g *FileGenerator) GenerateFileWithFuncs(templateName, outputPath string, d
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
-	dir := filepath.Dir(outputPath)
-	if err := utils.CreateDirIfNotExists(dir); err != nil {
+	dir := path.Dir(outputPath)
+	if err := g.fs.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 	
-	if err := os.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
+	if err := g.fs.WriteFile(outputPath, buf.Bytes(), 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 	
@@ -90,7 +96,7 @@ func (g *FileGenerator) GenerateMultipleFiles(templates map[string]string, outpu
 	g.logger.Debug("Generating multiple files in: %s", outputDir)
 
 	for templateName, outputFile := range templates {
-		outputPath := filepath.Join(outputDir, outputFile)
+		outputPath := path.Join(outputDir, outputFile)
 		if err := g.GenerateFile(templateName, outputPath, data); err != nil {
 			return fmt.Errorf("failed to generate file %s: %w", outputFile, err)
 		}
@@ -103,7 +109,7 @@ func (g *FileGenerator) GenerateMultipleFilesWithFuncs(templates map[string]stri
 	g.logger.Debug("Generating multiple files with funcs in: %s", outputDir)
 	
 	for templateName, outputFile := range templates {
-		outputPath := filepath.Join(outputDir, outputFile)
+		outputPath := path.Join(outputDir, outputFile)
 		if err := g.GenerateFileWithFuncs(templateName, outputPath, data, funcs); err != nil {
 			return fmt.Errorf("failed to generate file %s: %w", outputFile, err)
 		}
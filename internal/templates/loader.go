@@ -12,7 +12,7 @@ type TemplateLoader struct {
 
 func NewTemplateLoader() *TemplateLoader {
 	return &TemplateLoader{
-		logger: utils.NewLoggerWithPrefix(utils.Info, "TemplateLoader"),
+		logger: utils.NewComponentLogger("TemplateLoader"),
 	}
 }
 
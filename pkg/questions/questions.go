@@ -2,27 +2,162 @@ package questions
 
 import (
 	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
 
 	"github.com/AlecAivazis/survey/v2"
 )
 
+// ProjectAnswers holds everything asked while configuring a project.
+// Values maps question names to their answers.
 type ProjectAnswers struct {
 	ProjectName string
 	ProjectType string
-	UseZap      bool
-	UsePostgres bool
-	UseRedis    bool
-	UseRabbitMQ bool
+	Values      Answers
 }
 
-func AskProjectName() (string, error) {
+// Answers maps question names to answers of type bool, string, int or
+// []string. Questions skipped by their when condition have no entry.
+type Answers map[string]interface{}
+
+// Bool returns the answer to a bool question, or false.
+func (a Answers) Bool(name string) bool {
+	b, _ := a[name].(bool)
+	return b
+}
+
+// String returns the answer to a string or select question, or "".
+func (a Answers) String(name string) string {
+	s, _ := a[name].(string)
+	return s
+}
+
+// Int returns the answer to an int question, or 0.
+func (a Answers) Int(name string) int {
+	i, _ := a[name].(int)
+	return i
+}
+
+// Strings returns the answer to a multiselect question, or nil.
+func (a Answers) Strings(name string) []string {
+	s, _ := a[name].([]string)
+	return s
+}
+
+// ResolveAnswers builds the answers for a project type of the templates in
+// fsys from already known values, such as a decoded JSON request. Questions
+// without a value get their default. Unknown names and invalid values are rejected; values for
+// questions skipped by their when condition are ignored.
+func ResolveAnswers(fsys fs.FS, projectType string, values map[string]interface{}) (*ProjectAnswers, error) {
+	qs, err := ProjectQuestions(fsys, projectType)
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string]bool, len(qs))
+	answers := &ProjectAnswers{ProjectType: projectType, Values: Answers{}}
+	for i := range qs {
+		q := &qs[i]
+		known[q.Name] = true
+
+		enabled, err := q.Enabled(answers.Values)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			continue
+		}
+
+		value := q.Default
+		if raw, ok := values[q.Name]; ok {
+			if value, err = q.Normalize(raw); err != nil {
+				return nil, fmt.Errorf("invalid answer: %v", err)
+			}
+		}
+		answers.Values[q.Name] = value
+	}
+
+	var unknown []string
+	for name := range values {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown answers for %s project: %v", projectType, unknown)
+	}
+
+	return answers, nil
+}
+
+// JSONSchema describes the answers of a project type in fsys as a JSON
+// Schema object. When conditions have no JSON Schema equivalent and are
+// reported in the "x-when" keyword.
+func JSONSchema(fsys fs.FS, projectType string) (map[string]interface{}, error) {
+	qs, err := ProjectQuestions(fsys, projectType)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := make(map[string]interface{}, len(qs))
+	for _, q := range qs {
+		property := map[string]interface{}{
+			"title":       q.Message,
+			"description": q.Help,
+			"default":     q.Default,
+		}
+
+		switch q.Type {
+		case TypeBool:
+			property["type"] = "boolean"
+		case TypeString:
+			property["type"] = "string"
+			if q.Validate != "" {
+				property["pattern"] = q.Validate
+			}
+		case TypeInt:
+			property["type"] = "integer"
+		case TypeSelect:
+			property["type"] = "string"
+			property["enum"] = q.Options
+		case TypeMultiSelect:
+			property["type"] = "array"
+			property["items"] = map[string]interface{}{"type": "string", "enum": q.Options}
+			property["uniqueItems"] = true
+		}
+
+		if q.When != "" {
+			property["x-when"] = q.When
+		}
+		properties[q.Name] = property
+	}
+
+	return map[string]interface{}{
+		"$schema":              "https://json-schema.org/draft/2020-12/schema",
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}, nil
+}
+
+// WithStderr renders prompts on stderr, keeping stdout free for
+// machine-readable output such as archives.
+func WithStderr() survey.AskOpt {
+	return survey.WithStdio(os.Stdin, os.Stderr, os.Stderr)
+}
+
+func AskProjectName(opts ...survey.AskOpt) (string, error) {
 	var name string
 	prompt := &survey.Input{
 		Message: "What is your project name?",
 		Help:    "The name of your new project",
 	}
 
-	err := survey.AskOne(prompt, &name)
+	err := survey.AskOne(prompt, &name, opts...)
 	if err != nil {
 		return "", fmt.Errorf("failed to get project name: %v", err)
 	}
@@ -34,15 +169,21 @@ func AskProjectName() (string, error) {
 	return name, nil
 }
 
-func AskProjectType() (string, error) {
+// AskProjectType asks which of projectTypes to create, preselecting
+// defaultType when it is one of them.
+func AskProjectType(projectTypes []string, defaultType string, opts ...survey.AskOpt) (string, error) {
 	var projectType string
 	prompt := &survey.Select{
 		Message: "What type of project are you building?",
-		Options: []string{"api", "cli"},
-		Default: "api",
+		Options: projectTypes,
+	}
+	for _, t := range projectTypes {
+		if t == defaultType {
+			prompt.Default = defaultType
+		}
 	}
 
-	err := survey.AskOne(prompt, &projectType)
+	err := survey.AskOne(prompt, &projectType, opts...)
 	if err != nil {
 		return "", fmt.Errorf("failed to get project type: %v", err)
 	}
@@ -50,65 +191,100 @@ func AskProjectType() (string, error) {
 	return projectType, nil
 }
 
-func AskProjectQuestions(projectType string) (*ProjectAnswers, error) {
+func AskProjectQuestions(fsys fs.FS, projectType string, opts ...survey.AskOpt) (*ProjectAnswers, error) {
+	return AskMissingQuestions(fsys, projectType, nil, opts...)
+}
+
+// AskMissingQuestions asks the questions of a project type that known
+// does not answer already, such as those left open by a preset.
+func AskMissingQuestions(fsys fs.FS, projectType string, known map[string]interface{}, opts ...survey.AskOpt) (*ProjectAnswers, error) {
+	qs, err := ProjectQuestions(fsys, projectType)
+	if err != nil {
+		return nil, err
+	}
+
 	answers := &ProjectAnswers{
 		ProjectType: projectType,
+		Values:      Answers{},
 	}
 
-	switch projectType {
-	case "api":
-		prompt := &survey.Confirm{
-			Message: "Would you like to use zap as a logger?",
-			Default: true,
-		}
-		err := survey.AskOne(prompt, &answers.UseZap)
-		if err != nil {
-			return nil, err
-		}
+	names := make(map[string]bool, len(qs))
+	for i := range qs {
+		q := &qs[i]
+		names[q.Name] = true
 
-		prompt = &survey.Confirm{
-			Message: "Would you like to use PostgreSQL?",
-			Default: true,
-		}
-		err = survey.AskOne(prompt, &answers.UsePostgres)
+		enabled, err := q.Enabled(answers.Values)
 		if err != nil {
 			return nil, err
 		}
-
-		prompt = &survey.Confirm{
-			Message: "Would you like to use Redis?",
-			Default: false,
-		}
-		err = survey.AskOne(prompt, &answers.UseRedis)
-		if err != nil {
-			return nil, err
+		if !enabled {
+			continue
 		}
 
-		prompt = &survey.Confirm{
-			Message: "Would you like to use RabbitMQ?",
-			Default: false,
+		if raw, ok := known[q.Name]; ok {
+			value, err := q.Normalize(raw)
+			if err != nil {
+				return nil, fmt.Errorf("invalid answer: %v", err)
+			}
+			answers.Values[q.Name] = value
+			continue
 		}
-		err = survey.AskOne(prompt, &answers.UseRabbitMQ)
+
+		value, err := ask(q, opts...)
 		if err != nil {
 			return nil, err
 		}
-	case "cli":
+		answers.Values[q.Name] = value
+	}
 
-		prompt := &survey.Confirm{
-			Message: "Would you like to use zap as a logger?",
-			Default: false,
-		}
-		err := survey.AskOne(prompt, &answers.UseZap)
-		if err != nil {
-			return nil, err
+	for name := range known {
+		if !names[name] {
+			return nil, fmt.Errorf("unknown answer for %s project: %s", projectType, name)
 		}
+	}
+
+	return answers, nil
+}
+
+// ask prompts for a single question and returns the normalized answer.
+func ask(q *Question, opts ...survey.AskOpt) (interface{}, error) {
+	var prompt survey.Prompt
+	var answer interface{}
 
-		answers.UsePostgres = false
-		answers.UseRedis = false
-		answers.UseRabbitMQ = false
+	switch q.Type {
+	case TypeBool:
+		var value bool
+		prompt, answer = &survey.Confirm{Message: q.Message, Help: q.Help, Default: q.Default.(bool)}, &value
+	case TypeString:
+		var value string
+		prompt, answer = &survey.Input{Message: q.Message, Help: q.Help, Default: q.Default.(string)}, &value
+	case TypeInt:
+		var value string
+		prompt, answer = &survey.Input{Message: q.Message, Help: q.Help, Default: strconv.Itoa(q.Default.(int))}, &value
+	case TypeSelect:
+		var value string
+		prompt, answer = &survey.Select{Message: q.Message, Help: q.Help, Options: q.Options, Default: q.Default}, &value
+	case TypeMultiSelect:
+		var value []string
+		prompt, answer = &survey.MultiSelect{Message: q.Message, Help: q.Help, Options: q.Options, Default: q.Default}, &value
 	default:
-		return nil, fmt.Errorf("unsupported project type: %s", projectType)
+		return nil, fmt.Errorf("unknown question type %q", q.Type)
 	}
 
-	return answers, nil
+	validate := func(ans interface{}) error {
+		// Select prompts hand the validator an OptionAnswer; their
+		// options are valid by construction.
+		if s, ok := ans.(string); ok && (q.Type == TypeString || q.Type == TypeInt) {
+			_, err := q.Normalize(s)
+			return err
+		}
+		return nil
+	}
+
+	opts = append(opts, survey.WithValidator(validate))
+	if err := survey.AskOne(prompt, answer, opts...); err != nil {
+		return nil, err
+	}
+
+	return q.Normalize(reflect.ValueOf(answer).Elem().Interface())
 }
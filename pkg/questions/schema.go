@@ -0,0 +1,298 @@
+package questions
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaFile is the file, relative to a template directory, that declares
+// the questions of a project type.
+const SchemaFile = "questions.yaml"
+
+// Question types supported in a schema.
+const (
+	TypeBool        = "bool"
+	TypeString      = "string"
+	TypeInt         = "int"
+	TypeSelect      = "select"
+	TypeMultiSelect = "multiselect"
+)
+
+var questionNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Question is a single question declared by a template. Name is the key
+// the answer is stored under and the name templates refer to it by.
+type Question struct {
+	Name     string      `yaml:"name"`
+	Type     string      `yaml:"type"`
+	Message  string      `yaml:"message"`
+	Help     string      `yaml:"help"`
+	Default  interface{} `yaml:"default"`
+	Options  []string    `yaml:"options"`
+	Validate string      `yaml:"validate"`
+	// When is a text/template condition evaluated against the answers
+	// given so far, such as ".UsePostgres" or `eq .Logger "zap"`. The
+	// question is skipped when it is false.
+	When string `yaml:"when"`
+
+	pattern *regexp.Regexp
+	when    *template.Template
+}
+
+// Schema is the list of questions of a project type, in the order they
+// are asked.
+type Schema struct {
+	Questions []Question `yaml:"questions"`
+}
+
+// ParseSchema parses and validates a question schema.
+func ParseSchema(data []byte) (*Schema, error) {
+	var schema Schema
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&schema); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse question schema: %v", err)
+	}
+
+	seen := make(map[string]bool, len(schema.Questions))
+	for i := range schema.Questions {
+		q := &schema.Questions[i]
+		if err := q.compile(); err != nil {
+			return nil, fmt.Errorf("question %q: %v", q.Name, err)
+		}
+		if seen[q.Name] {
+			return nil, fmt.Errorf("question %q is declared twice", q.Name)
+		}
+		seen[q.Name] = true
+	}
+
+	return &schema, nil
+}
+
+// LoadSchema reads the question schema of the template directory dir in
+// fsys. A directory without a schema asks no questions.
+func LoadSchema(fsys fs.FS, dir string) (*Schema, error) {
+	data, err := fs.ReadFile(fsys, path.Join(dir, SchemaFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Schema{}, nil
+		}
+		return nil, err
+	}
+
+	schema, err := ParseSchema(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path.Join(dir, SchemaFile), err)
+	}
+	return schema, nil
+}
+
+// ProjectQuestions returns the questions the template of a project type in
+// fsys declares, in the order they are asked.
+func ProjectQuestions(fsys fs.FS, projectType string) ([]Question, error) {
+	if _, err := fs.Stat(fsys, projectType); err != nil {
+		return nil, fmt.Errorf("unsupported project type: %s", projectType)
+	}
+
+	schema, err := LoadSchema(fsys, projectType)
+	if err != nil {
+		return nil, err
+	}
+	return schema.Questions, nil
+}
+
+func (q *Question) compile() error {
+	if !questionNamePattern.MatchString(q.Name) {
+		return fmt.Errorf("name must be a letter followed by letters, digits or underscores")
+	}
+	if q.Message == "" {
+		return fmt.Errorf("message is required")
+	}
+
+	switch q.Type {
+	case TypeBool, TypeString, TypeInt:
+		if len(q.Options) > 0 {
+			return fmt.Errorf("options are only allowed for select and multiselect questions")
+		}
+	case TypeSelect, TypeMultiSelect:
+		if len(q.Options) == 0 {
+			return fmt.Errorf("%s questions need options", q.Type)
+		}
+	default:
+		return fmt.Errorf("unknown type %q", q.Type)
+	}
+
+	if q.Validate != "" {
+		if q.Type != TypeString {
+			return fmt.Errorf("validate is only allowed for string questions")
+		}
+		pattern, err := regexp.Compile(q.Validate)
+		if err != nil {
+			return fmt.Errorf("invalid validate pattern: %v", err)
+		}
+		q.pattern = pattern
+	}
+
+	if q.When != "" {
+		when, err := template.New(q.Name).Option("missingkey=zero").Parse("{{if " + q.When + "}}true{{end}}")
+		if err != nil {
+			return fmt.Errorf("invalid when condition: %v", err)
+		}
+		q.when = when
+	}
+
+	if q.Default == nil {
+		q.Default = q.zero()
+	}
+	value, err := q.Normalize(q.Default)
+	if err != nil {
+		return fmt.Errorf("invalid default: %v", err)
+	}
+	q.Default = value
+
+	return nil
+}
+
+// zero returns the default of a question that declares none.
+func (q *Question) zero() interface{} {
+	switch q.Type {
+	case TypeBool:
+		return false
+	case TypeInt:
+		return 0
+	case TypeSelect:
+		return q.Options[0]
+	case TypeMultiSelect:
+		return []string{}
+	}
+	return ""
+}
+
+// Enabled reports whether the question applies given the answers so far.
+func (q *Question) Enabled(answers Answers) (bool, error) {
+	if q.when == nil {
+		return true, nil
+	}
+
+	var buf bytes.Buffer
+	if err := q.when.Execute(&buf, map[string]interface{}(answers)); err != nil {
+		return false, fmt.Errorf("failed to evaluate when condition of %s: %v", q.Name, err)
+	}
+	return buf.String() == "true", nil
+}
+
+// Normalize checks that value is a valid answer and converts it to the
+// question's Go type: bool, string, int or []string. Numbers decoded from
+// JSON or YAML and strings typed at a prompt are accepted for int
+// questions.
+func (q *Question) Normalize(value interface{}) (interface{}, error) {
+	switch q.Type {
+	case TypeBool:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a boolean", q.Name)
+		}
+		return b, nil
+
+	case TypeString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", q.Name)
+		}
+		if q.pattern != nil && !q.pattern.MatchString(s) {
+			return nil, fmt.Errorf("%s must match %s", q.Name, q.Validate)
+		}
+		return s, nil
+
+	case TypeInt:
+		switch n := value.(type) {
+		case int:
+			return n, nil
+		case int64:
+			return int(n), nil
+		case float64:
+			if n == math.Trunc(n) {
+				return int(n), nil
+			}
+		case string:
+			if i, err := strconv.Atoi(n); err == nil {
+				return i, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be an integer", q.Name)
+
+	case TypeSelect:
+		s, ok := value.(string)
+		if !ok || !q.hasOption(s) {
+			return nil, fmt.Errorf("%s must be one of %v", q.Name, q.Options)
+		}
+		return s, nil
+
+	case TypeMultiSelect:
+		var items []interface{}
+		switch v := value.(type) {
+		case []string:
+			for _, s := range v {
+				items = append(items, s)
+			}
+		case []interface{}:
+			items = v
+		default:
+			return nil, fmt.Errorf("%s must be a list of %v", q.Name, q.Options)
+		}
+
+		selected := make([]string, 0, len(items))
+		for _, item := range items {
+			s, ok := item.(string)
+			if !ok || !q.hasOption(s) {
+				return nil, fmt.Errorf("%s must be a list of %v", q.Name, q.Options)
+			}
+			selected = append(selected, s)
+		}
+		return selected, nil
+	}
+
+	return nil, fmt.Errorf("unknown question type %q", q.Type)
+}
+
+// ParseString converts an answer typed on the command line, such as
+// "true", "8080" or "cors,recover", and normalizes it.
+func (q *Question) ParseString(s string) (interface{}, error) {
+	switch q.Type {
+	case TypeBool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", q.Name)
+		}
+		return b, nil
+	case TypeMultiSelect:
+		items := []string{}
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return q.Normalize(items)
+	}
+	return q.Normalize(s)
+}
+
+func (q *Question) hasOption(s string) bool {
+	for _, option := range q.Options {
+		if option == s {
+			return true
+		}
+	}
+	return false
+}
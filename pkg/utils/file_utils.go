@@ -4,9 +4,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"github.com/go-sova/sova-cli/pkg/vfs"
 )
 
 func FileExists(filename string) bool {
@@ -32,6 +35,14 @@ func CreateDirIfNotExists(dirname string) error {
 	return nil
 }
 
+// CreateDirIfNotExistsIn is CreateDirIfNotExists for a directory inside fsys.
+func CreateDirIfNotExistsIn(fsys vfs.FS, dirname string) error {
+	if !vfs.IsDir(fsys, dirname) {
+		return fsys.MkdirAll(dirname, 0755)
+	}
+	return nil
+}
+
 func WriteFile(filename string, data []byte) error {
 	dir := filepath.Dir(filename)
 	if err := CreateDirIfNotExists(dir); err != nil {
@@ -82,6 +93,30 @@ func CopyFile(src, dst string) error {
 	return os.Chmod(dst, srcInfo.Mode())
 }
 
+// CopyFileTo copies the regular file src from the operating system's
+// filesystem to dst inside fsys, keeping its permission bits.
+func CopyFileTo(fsys vfs.FS, src, dst string) error {
+	srcInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if !srcInfo.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+
+	if err := CreateDirIfNotExistsIn(fsys, path.Dir(dst)); err != nil {
+		return err
+	}
+
+	return fsys.WriteFile(dst, data, srcInfo.Mode().Perm())
+}
+
 func CopyDir(src, dst string) error {
 	srcInfo, err := os.Stat(src)
 	if err != nil {
@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
@@ -27,8 +30,68 @@ var levelNames = map[LogLevel]string{
 	Fatal:   "FATAL",
 }
 
+// LogFormat selects how a Logger writes its entries.
+type LogFormat int
+
+const (
+	// TextFormat writes one human-readable line per entry, colored when
+	// written to a terminal.
+	TextFormat LogFormat = iota
+	// JSONFormat writes one JSON object per line with the fields time,
+	// level, component and msg.
+	JSONFormat
+)
+
+// ParseLogFormat parses "text" or "json".
+func ParseLogFormat(s string) (LogFormat, error) {
+	switch strings.ToLower(s) {
+	case "text", "":
+		return TextFormat, nil
+	case "json":
+		return JSONFormat, nil
+	default:
+		return TextFormat, fmt.Errorf("invalid log format %q: use text or json", s)
+	}
+}
+
+// LoggerOptions configure the loggers of sova's components.
+type LoggerOptions struct {
+	Level  LogLevel
+	Format LogFormat
+	Output io.Writer
+}
+
+var (
+	optionsMu      sync.Mutex
+	defaultOptions = LoggerOptions{Level: Info, Format: TextFormat, Output: os.Stderr}
+)
+
+// ConfigureLogging sets the options of DefaultLogger and of the loggers
+// NewComponentLogger creates from now on.
+func ConfigureLogging(opts LoggerOptions) {
+	if opts.Output == nil {
+		opts.Output = os.Stderr
+	}
+
+	optionsMu.Lock()
+	defaultOptions = opts
+	optionsMu.Unlock()
+
+	DefaultLogger.SetLevel(opts.Level)
+	DefaultLogger.SetFormat(opts.Format)
+	DefaultLogger.SetOutput(opts.Output)
+}
+
+// LoggingOptions returns the options set with ConfigureLogging.
+func LoggingOptions() LoggerOptions {
+	optionsMu.Lock()
+	defer optionsMu.Unlock()
+	return defaultOptions
+}
+
 type Logger struct {
 	level  LogLevel
+	format LogFormat
 	output io.Writer
 	prefix string
 }
@@ -49,6 +112,19 @@ func NewLoggerWithPrefix(level LogLevel, prefix string) *Logger {
 	}
 }
 
+// NewComponentLogger returns a logger for the named component that uses
+// the options set with ConfigureLogging, so --verbose and --debug reach
+// every component.
+func NewComponentLogger(prefix string) *Logger {
+	opts := LoggingOptions()
+	return &Logger{
+		level:  opts.Level,
+		format: opts.Format,
+		output: opts.Output,
+		prefix: prefix,
+	}
+}
+
 func (l *Logger) SetOutput(output io.Writer) {
 	l.output = output
 }
@@ -57,35 +133,60 @@ func (l *Logger) SetLevel(level LogLevel) {
 	l.level = level
 }
 
+func (l *Logger) SetFormat(format LogFormat) {
+	l.format = format
+}
+
 func (l *Logger) SetPrefix(prefix string) {
 	l.prefix = prefix
 }
 
 func (l *Logger) Log(level LogLevel, format string, args ...interface{}) {
-	if level >= l.level {
-		timestamp := time.Now().Format("2006-01-02 15:04:05")
-		prefix := ""
-		if l.prefix != "" {
-			prefix = fmt.Sprintf("[%s] ", l.prefix)
-		}
+	if level < l.level {
+		return
+	}
+
+	now := time.Now()
+	message := fmt.Sprintf(format, args...)
+
+	if l.format == JSONFormat {
+		entry := struct {
+			Time      string `json:"time"`
+			Level     string `json:"level"`
+			Component string `json:"component,omitempty"`
+			Message   string `json:"msg"`
+		}{now.Format(time.RFC3339), strings.ToLower(levelNames[level]), l.prefix, message}
 
-		message := fmt.Sprintf(format, args...)
-		logLine := fmt.Sprintf("%s %s[%s] %s\n", timestamp, prefix, levelNames[level], message)
-
-		switch level {
-		case Debug:
-			fmt.Fprint(l.output, logLine)
-		case Info:
-			color.New(color.FgBlue).Fprint(l.output, logLine)
-		case Warning:
-			color.New(color.FgYellow).Fprint(l.output, logLine)
-		case Error, Fatal:
-			color.New(color.FgRed).Fprint(l.output, logLine)
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return
 		}
+		l.output.Write(append(data, '\n'))
+		return
+	}
+
+	timestamp := now.Format("2006-01-02 15:04:05")
+	prefix := ""
+	if l.prefix != "" {
+		prefix = fmt.Sprintf("[%s] ", l.prefix)
+	}
+	logLine := fmt.Sprintf("%s %s[%s] %s\n", timestamp, prefix, levelNames[level], message)
+
+	// Only color lines written to the terminal, not to log files.
+	if l.output != os.Stderr && l.output != os.Stdout {
+		fmt.Fprint(l.output, logLine)
+		return
 	}
 
-	if level == Fatal {
-		os.Exit(1)
+	switch level {
+	case Debug:
+		fmt.Fprint(l.output, logLine)
+	case Info:
+		color.New(color.FgBlue).Fprint(l.output, logLine)
+	case Warning:
+		color.New(color.FgYellow).Fprint(l.output, logLine)
+	case Error, Fatal:
+		color.New(color.FgRed).Fprint(l.output, logLine)
 	}
 }
 
@@ -105,8 +206,11 @@ func (l *Logger) Error(format string, args ...interface{}) {
 	l.Log(Error, format, args...)
 }
 
-func (l *Logger) Fatal(format string, args ...interface{}) {
+// Fatal logs the message and returns it as an error. It does not exit;
+// callers return the error so it reaches the command that failed.
+func (l *Logger) Fatal(format string, args ...interface{}) error {
 	l.Log(Fatal, format, args...)
+	return fmt.Errorf(format, args...)
 }
 
 var DefaultLogger = NewLogger(Info)
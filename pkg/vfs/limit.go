@@ -0,0 +1,60 @@
+package vfs
+
+import (
+	"errors"
+	"io/fs"
+	"sync"
+)
+
+// ErrTooLarge is the error of a LimitFS write that would take the files of
+// the filesystem past its limit.
+var ErrTooLarge = errors.New("size limit exceeded")
+
+// LimitFS caps the total size of the files written to a filesystem. A
+// write that would take it past the limit fails before reaching the
+// filesystem, so a generation stops at the first file too many rather than
+// once everything is rendered.
+type LimitFS struct {
+	FS
+	limit int64
+
+	mu       sync.Mutex
+	sizes    map[string]int64
+	size     int64
+	exceeded bool
+}
+
+// NewLimitFS returns a filesystem writing to fsys as long as the files
+// written through it add up to at most limit bytes.
+func NewLimitFS(fsys FS, limit int64) *LimitFS {
+	return &LimitFS{
+		FS:    fsys,
+		limit: limit,
+		sizes: make(map[string]int64),
+	}
+}
+
+func (l *LimitFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Rewriting a file replaces its size rather than adding to it
+	size := l.size - l.sizes[name] + int64(len(data))
+	if size > l.limit {
+		l.exceeded = true
+		return &fs.PathError{Op: "write", Path: name, Err: ErrTooLarge}
+	}
+	if err := l.FS.WriteFile(name, data, perm); err != nil {
+		return err
+	}
+	l.sizes[name] = int64(len(data))
+	l.size = size
+	return nil
+}
+
+// Exceeded reports whether a write failed for going past the limit.
+func (l *LimitFS) Exceeded() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.exceeded
+}
@@ -0,0 +1,214 @@
+package vfs
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemFS is an in-memory filesystem. Its root always exists. It is safe for
+// concurrent use.
+type MemFS struct {
+	mu    sync.RWMutex
+	files map[string]*memFile
+	dirs  map[string]fs.FileMode
+}
+
+type memFile struct {
+	data []byte
+	mode fs.FileMode
+}
+
+// NewMemFS returns an empty in-memory filesystem.
+func NewMemFS() *MemFS {
+	return &MemFS{
+		files: make(map[string]*memFile),
+		dirs:  map[string]fs.FileMode{".": fs.ModeDir | 0755},
+	}
+}
+
+func (m *MemFS) MkdirAll(name string, perm fs.FileMode) error {
+	if err := validPath("mkdir", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, dir := range append(parents(name), name) {
+		if _, ok := m.files[dir]; ok {
+			return &fs.PathError{Op: "mkdir", Path: dir, Err: fs.ErrExist}
+		}
+		if _, ok := m.dirs[dir]; !ok {
+			m.dirs[dir] = fs.ModeDir | perm.Perm()
+		}
+	}
+
+	return nil
+}
+
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := validPath("write", name); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.dirs[name]; ok {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrExist}
+	}
+	if _, ok := m.dirs[path.Dir(name)]; !ok {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrNotExist}
+	}
+
+	m.files[name] = &memFile{
+		data: bytes.Clone(data),
+		mode: perm.Perm(),
+	}
+	return nil
+}
+
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if err := validPath("open", name); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if f, ok := m.files[name]; ok {
+		return &memOpenFile{
+			info:   memInfo{name: path.Base(name), size: int64(len(f.data)), mode: f.mode},
+			Reader: bytes.NewReader(f.data),
+		}, nil
+	}
+
+	if mode, ok := m.dirs[name]; ok {
+		return &memOpenDir{
+			info:    memInfo{name: path.Base(name), mode: mode},
+			entries: m.readDir(name),
+		}, nil
+	}
+
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (m *MemFS) ReadFile(name string) ([]byte, error) {
+	if err := validPath("read", name); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	f, ok := m.files[name]
+	if !ok {
+		if _, isDir := m.dirs[name]; isDir {
+			return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrInvalid}
+		}
+		return nil, &fs.PathError{Op: "read", Path: name, Err: fs.ErrNotExist}
+	}
+	return bytes.Clone(f.data), nil
+}
+
+// Size returns the total number of bytes stored in files.
+func (m *MemFS) Size() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var size int64
+	for _, f := range m.files {
+		size += int64(len(f.data))
+	}
+	return size
+}
+
+// readDir lists the direct children of dir. The caller holds m.mu.
+func (m *MemFS) readDir(dir string) []fs.DirEntry {
+	prefix := dir + "/"
+	if dir == "." {
+		prefix = ""
+	}
+
+	var entries []fs.DirEntry
+	for name, f := range m.files {
+		if child, ok := directChild(prefix, name); ok {
+			entries = append(entries, fs.FileInfoToDirEntry(memInfo{name: child, size: int64(len(f.data)), mode: f.mode}))
+		}
+	}
+	for name, mode := range m.dirs {
+		if child, ok := directChild(prefix, name); ok {
+			entries = append(entries, fs.FileInfoToDirEntry(memInfo{name: child, mode: mode}))
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries
+}
+
+func directChild(prefix, name string) (string, bool) {
+	if name == "." || !strings.HasPrefix(name, prefix) {
+		return "", false
+	}
+	rest := name[len(prefix):]
+	if rest == "" || strings.Contains(rest, "/") {
+		return "", false
+	}
+	return rest, true
+}
+
+type memInfo struct {
+	name string
+	size int64
+	mode fs.FileMode
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() fs.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return time.Time{} }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
+
+type memOpenFile struct {
+	info memInfo
+	*bytes.Reader
+}
+
+func (f *memOpenFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *memOpenFile) Close() error               { return nil }
+
+type memOpenDir struct {
+	info    memInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memOpenDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memOpenDir) Close() error               { return nil }
+
+func (d *memOpenDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *memOpenDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	remaining := d.entries[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+	d.offset += count
+	return remaining[:count], nil
+}
@@ -0,0 +1,57 @@
+package vfs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// OSFS writes to the operating system's filesystem below a root directory.
+type OSFS struct {
+	root string
+	dir  fs.FS
+}
+
+// NewOSFS returns a filesystem rooted at the given directory. The directory
+// does not have to exist yet; MkdirAll(".") creates it.
+func NewOSFS(root string) *OSFS {
+	return &OSFS{
+		root: root,
+		dir:  os.DirFS(root),
+	}
+}
+
+// Root returns the directory the filesystem is rooted at.
+func (f *OSFS) Root() string {
+	return f.root
+}
+
+func (f *OSFS) Open(name string) (fs.File, error) {
+	return f.dir.Open(name)
+}
+
+func (f *OSFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(f.dir, name)
+}
+
+func (f *OSFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(f.dir, name)
+}
+
+func (f *OSFS) MkdirAll(name string, perm fs.FileMode) error {
+	if err := validPath("mkdir", name); err != nil {
+		return err
+	}
+	return os.MkdirAll(f.osPath(name), perm)
+}
+
+func (f *OSFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := validPath("write", name); err != nil {
+		return err
+	}
+	return os.WriteFile(f.osPath(name), data, perm)
+}
+
+func (f *OSFS) osPath(name string) string {
+	return filepath.Join(f.root, filepath.FromSlash(name))
+}
@@ -0,0 +1,106 @@
+package vfs
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+// OverlayFS layers a writable filesystem over a read-only one. Reads see
+// the upper layer first and fall back to the lower layer; writes only ever
+// reach the upper layer, leaving the lower one untouched.
+type OverlayFS struct {
+	upper FS
+	lower fs.FS
+}
+
+// NewOverlayFS returns a filesystem that writes to upper and reads through
+// to lower.
+func NewOverlayFS(upper FS, lower fs.FS) *OverlayFS {
+	return &OverlayFS{
+		upper: upper,
+		lower: lower,
+	}
+}
+
+// Upper returns the layer that receives all writes.
+func (o *OverlayFS) Upper() FS {
+	return o.upper
+}
+
+func (o *OverlayFS) Open(name string) (fs.File, error) {
+	if err := validPath("open", name); err != nil {
+		return nil, err
+	}
+
+	upperInfo, upperErr := fs.Stat(o.upper, name)
+	lowerInfo, lowerErr := fs.Stat(o.lower, name)
+
+	switch {
+	case upperErr == nil && upperInfo.IsDir() && lowerErr == nil && lowerInfo.IsDir():
+		entries, err := o.mergedEntries(name)
+		if err != nil {
+			return nil, err
+		}
+		return &memOpenDir{
+			info:    memInfo{name: path.Base(name), mode: upperInfo.Mode()},
+			entries: entries,
+		}, nil
+	case upperErr == nil:
+		return o.upper.Open(name)
+	case errors.Is(upperErr, fs.ErrNotExist):
+		return o.lower.Open(name)
+	default:
+		return nil, upperErr
+	}
+}
+
+func (o *OverlayFS) MkdirAll(name string, perm fs.FileMode) error {
+	return o.upper.MkdirAll(name, perm)
+}
+
+// WriteFile writes name to the upper layer. The parent directory may exist
+// in either layer; it is created in the upper layer when needed.
+func (o *OverlayFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if err := validPath("write", name); err != nil {
+		return err
+	}
+
+	dir := path.Dir(name)
+	if !IsDir(o.upper, dir) {
+		if !IsDir(o.lower, dir) {
+			return &fs.PathError{Op: "write", Path: name, Err: fs.ErrNotExist}
+		}
+		if err := o.upper.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return o.upper.WriteFile(name, data, perm)
+}
+
+func (o *OverlayFS) mergedEntries(name string) ([]fs.DirEntry, error) {
+	upper, err := fs.ReadDir(o.upper, name)
+	if err != nil {
+		return nil, err
+	}
+	lower, err := fs.ReadDir(o.lower, name)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(upper))
+	entries := append([]fs.DirEntry(nil), upper...)
+	for _, entry := range upper {
+		seen[entry.Name()] = true
+	}
+	for _, entry := range lower {
+		if !seen[entry.Name()] {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, nil
+}
@@ -0,0 +1,60 @@
+// Package vfs provides the writable filesystems generated projects are
+// rendered into. Paths are slash-separated and relative to the filesystem
+// root, following the io/fs conventions.
+package vfs
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+)
+
+// FS is a filesystem that generators can read from and write to.
+type FS interface {
+	fs.FS
+
+	// MkdirAll creates the directory name along with any missing parents.
+	MkdirAll(name string, perm fs.FileMode) error
+
+	// WriteFile writes data to name, replacing any existing file. The
+	// parent directory must already exist.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// Exists reports whether name exists in fsys.
+func Exists(fsys fs.FS, name string) bool {
+	_, err := fs.Stat(fsys, name)
+	return err == nil
+}
+
+// IsDir reports whether name exists in fsys and is a directory.
+func IsDir(fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+	return err == nil && info.IsDir()
+}
+
+// IsEmpty reports whether the root of fsys has no entries. A root that does
+// not exist yet is empty.
+func IsEmpty(fsys fs.FS) bool {
+	entries, err := fs.ReadDir(fsys, ".")
+	if errors.Is(err, fs.ErrNotExist) {
+		return true
+	}
+	return err == nil && len(entries) == 0
+}
+
+func validPath(op, name string) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return nil
+}
+
+// parents returns every ancestor of name, outermost first, excluding ".".
+func parents(name string) []string {
+	var dirs []string
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		dirs = append([]string{dir}, dirs...)
+	}
+	return dirs
+}
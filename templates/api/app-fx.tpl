@@ -0,0 +1,208 @@
+// Package app is the application container. Uber fx builds it: it calls
+// the constructors below in the order their results are needed, passing
+// each the values it asks for, starts the server once they all succeeded
+// and closes the connections when the application stops. No package keeps
+// them in globals.
+package app
+
+import (
+	"context"
+{{- if .UsePostgres}}
+	"database/sql"
+{{- end}}
+{{- if or .UsePostgres .UseRedis .UseRabbitMQ (ne .Auth "none") .UseMiddleware.ratelimit}}
+	"fmt"
+{{- end}}
+	"log"
+	"net"
+{{- if or .UsePostgres .UseRedis .UseRabbitMQ}}
+	"time"
+{{- end}}
+{{if ne .Auth "none"}}
+	"github.com/gin-gonic/gin"
+{{- end}}
+{{- if .UseRabbitMQ}}
+	amqp "github.com/rabbitmq/amqp091-go"
+{{- end}}
+{{- if .UseRedis}}
+	"github.com/redis/go-redis/v9"
+{{- end}}
+	"go.uber.org/fx"
+
+	"{{.ModuleName}}/internal/handlers"
+	"{{.ModuleName}}/internal/health"
+	"{{.ModuleName}}/internal/logging"
+{{- if or (ne .Auth "none") .UseMiddleware.ratelimit}}
+	"{{.ModuleName}}/internal/middleware"
+{{- end}}
+{{- if or .UsePostgres .UseRedis .UseRabbitMQ}}
+	"{{.ModuleName}}/internal/platform"
+{{- end}}
+	"{{.ModuleName}}/internal/repository"
+	"{{.ModuleName}}/internal/routes"
+	"{{.ModuleName}}/internal/server"
+	"{{.ModuleName}}/internal/service"
+)
+
+// New returns the application, logging through logger. Run it with Run,
+// which stops it on SIGINT or SIGTERM.
+func New(logger logging.Logger) *fx.App {
+	return fx.New(
+		fx.Supply(logger),
+		fx.Provide(
+			health.NewRegistry,
+{{- if .UsePostgres}}
+			newPostgres,
+{{- end}}
+{{- if .UseRedis}}
+			newRedis,
+{{- end}}
+{{- if .UseRabbitMQ}}
+			newRabbitMQ,
+{{- end}}
+			repository.New,
+			service.New,
+			handlers.New,
+{{- if .OpenAPI}}
+			handlers.NewAPI,
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+			newLimiter,
+{{- end}}
+{{- if ne .Auth "none"}}
+			newAuth,
+{{- end}}
+			newServer,
+		),
+		fx.Invoke(runServer),
+	)
+}
+{{- if .UsePostgres}}
+
+// newPostgres connects to PostgreSQL, registers its readiness check and
+// closes it when the application stops.
+func newPostgres(lc fx.Lifecycle, checks *health.Registry) (*sql.DB, error) {
+	db, err := platform.NewPostgres(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %v", err)
+	}
+	checks.Register("postgres", 2*time.Second, db.PingContext)
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return db.Close()
+		},
+	})
+	return db, nil
+}
+{{- end}}
+{{- if .UseRedis}}
+
+// newRedis connects to Redis, registers its readiness check and closes it
+// when the application stops.
+func newRedis(lc fx.Lifecycle, checks *health.Registry) (*redis.Client, error) {
+	client, err := platform.NewRedis(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+	checks.Register("redis", 2*time.Second, func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return client.Close()
+		},
+	})
+	return client, nil
+}
+{{- end}}
+{{- if .UseRabbitMQ}}
+
+// newRabbitMQ connects to RabbitMQ, registers its readiness check and
+// closes the connection when the application stops.
+func newRabbitMQ(lc fx.Lifecycle, checks *health.Registry) (*amqp.Connection, error) {
+	conn, err := platform.NewRabbitMQ()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
+	}
+	checks.Register("rabbitmq", 2*time.Second, func(ctx context.Context) error {
+		return platform.PingRabbitMQ(conn)
+	})
+	lc.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			return conn.Close()
+		},
+	})
+	return conn, nil
+}
+{{- end}}
+
+{{- if ne .Auth "none"}}
+
+// newAuth returns the middleware authenticating the callers of the private
+// routes. Its background work stops when the application stops.
+func newAuth(lc fx.Lifecycle) (gin.HandlerFunc, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	auth, err := middleware.Auth(ctx)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to set up authentication: %v", err)
+	}
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+	return auth, nil
+}
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+
+// newLimiter returns the limiter of the rate limiting middleware.
+func newLimiter({{if .UseRedis}}client *redis.Client{{end}}) (middleware.Limiter, error) {
+	limiter, err := middleware.LimiterFromEnv({{if .UseRedis}}client{{end}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up rate limiting: %v", err)
+	}
+	return limiter, nil
+}
+{{- end}}
+
+// newServer returns the server of the routes of h{{if .OpenAPI}} and api{{end}}.
+func newServer(logger logging.Logger, h *handlers.Handlers{{if .OpenAPI}}, api *handlers.API{{end}}{{if .UseMiddleware.ratelimit}}, limiter middleware.Limiter{{end}}{{if ne .Auth "none"}}, auth gin.HandlerFunc{{end}}) (*server.Server, error) {
+	return server.NewServer(routes.Dependencies{
+		Logger:   logger,
+		Handlers: h,
+{{- if .OpenAPI}}
+		API:      api,
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+		Limiter:  limiter,
+{{- end}}
+{{- if ne .Auth "none"}}
+		Auth:     auth,
+{{- end}}
+	})
+}
+
+// runServer starts srv when the application starts and shuts it down when
+// it stops, letting the requests in flight finish. A server failing later
+// stops the application.
+func runServer(lc fx.Lifecycle, shutdowner fx.Shutdowner, srv *server.Server) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", srv.Addr())
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := srv.Serve(ln); err != nil {
+					log.Printf("Server failed: %v", err)
+					shutdowner.Shutdown(fx.ExitCode(1))
+				}
+			}()
+			return nil
+		},
+		OnStop: srv.Shutdown,
+	})
+}
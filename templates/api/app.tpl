@@ -0,0 +1,143 @@
+// Package app is the application container: it connects to the
+// dependencies of the application and passes them to the constructors of
+// the repositories, services, handlers and server, so no package keeps
+// them in globals.
+package app
+
+import (
+	"context"
+{{- if .UsePostgres}}
+	"database/sql"
+{{- end}}
+	"time"
+{{- if .UseRabbitMQ}}
+
+	amqp "github.com/rabbitmq/amqp091-go"
+{{- end}}
+{{- if .UseRedis}}
+{{- if not .UseRabbitMQ}}
+{{end}}
+	"github.com/redis/go-redis/v9"
+{{- end}}
+{{- if eq .DI "manual"}}
+
+	"{{.ModuleName}}/internal/handlers"
+	"{{.ModuleName}}/internal/health"
+	"{{.ModuleName}}/internal/logging"
+	"{{.ModuleName}}/internal/repository"
+	"{{.ModuleName}}/internal/server"
+	"{{.ModuleName}}/internal/service"
+{{- else}}
+
+	"{{.ModuleName}}/internal/server"
+{{- end}}
+)
+
+// App is the application and the connections it holds.
+type App struct {
+	Server *server.Server
+{{- if .UsePostgres}}
+	// DB is the PostgreSQL database
+	DB *sql.DB
+{{- end}}
+{{- if .UseRedis}}
+	// Redis is the Redis client
+	Redis *redis.Client
+{{- end}}
+{{- if .UseRabbitMQ}}
+	// RabbitMQ is the connection to the RabbitMQ broker
+	RabbitMQ *amqp.Connection
+{{- end}}
+}
+{{- if eq .DI "manual"}}
+
+// New connects to the dependencies of the application and builds it.
+// Close the returned App once done.
+func New(ctx context.Context, logger logging.Logger) (*App, error) {
+	a := &App{}
+	checks := health.NewRegistry()
+	var err error
+{{- if .UsePostgres}}
+	if a.DB, err = newPostgres(ctx, checks); err != nil {
+		a.Close()
+		return nil, err
+	}
+{{- end}}
+{{- if .UseRedis}}
+	if a.Redis, err = newRedis(ctx, checks); err != nil {
+		a.Close()
+		return nil, err
+	}
+{{- end}}
+{{- if .UseRabbitMQ}}
+	if a.RabbitMQ, err = newRabbitMQ(checks); err != nil {
+		a.Close()
+		return nil, err
+	}
+{{- end}}
+
+	repos := repository.New({{if .UsePostgres}}a.DB{{end}})
+	services := service.New(repos{{if .UseRedis}}, a.Redis{{end}}{{if .UseRabbitMQ}}, a.RabbitMQ{{end}})
+	h := handlers.New(services, checks)
+{{- if .OpenAPI}}
+	api := handlers.NewAPI(services)
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+	limiter, err := newLimiter({{if .UseRedis}}a.Redis{{end}})
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+{{- end}}
+{{- if ne .Auth "none"}}
+	auth, err := newAuth(ctx)
+	if err != nil {
+		a.Close()
+		return nil, err
+	}
+{{- end}}
+	if a.Server, err = newServer(logger, h{{if .OpenAPI}}, api{{end}}{{if .UseMiddleware.ratelimit}}, limiter{{end}}{{if ne .Auth "none"}}, auth{{end}}); err != nil {
+		a.Close()
+		return nil, err
+	}
+	return a, nil
+}
+{{- end}}
+
+// Run serves requests until ctx is done, then shuts the server down,
+// letting the requests in flight finish for up to 10 seconds.
+func (a *App) Run(ctx context.Context) error {
+	errc := make(chan error, 1)
+	go func() {
+		errc <- a.Server.Start()
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return a.Server.Shutdown(shutdownCtx)
+}
+
+// Close closes the connections of the application.
+func (a *App) Close() {
+{{- if .UseRabbitMQ}}
+	if a.RabbitMQ != nil {
+		a.RabbitMQ.Close()
+	}
+{{- end}}
+{{- if .UseRedis}}
+	if a.Redis != nil {
+		a.Redis.Close()
+	}
+{{- end}}
+{{- if .UsePostgres}}
+	if a.DB != nil {
+		a.DB.Close()
+	}
+{{- end}}
+}
@@ -0,0 +1,131 @@
+package apperror
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	// Name invalid fields after their json tags, as clients know them
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+	}
+}
+
+// FromBinding turns an error of c.ShouldBindJSON and similar into a 400
+// Validation error saying which fields are invalid and why, or a 413 for
+// bodies over the limit of http.MaxBytesReader.
+func FromBinding(err error) *Error {
+	var invalid validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		return New(http.StatusRequestEntityTooLarge, fmt.Sprintf("The request body is larger than %d bytes", tooLarge.Limit)).Wrap(err)
+	case errors.As(err, &invalid):
+		fields := make([]FieldError, 0, len(invalid))
+		for _, fe := range invalid {
+			fields = append(fields, FieldError{Field: fieldPath(fe), Message: validationMessage(fe)})
+		}
+		return Validation("The request has invalid fields", fields...).Wrap(err)
+	case errors.As(err, &typeErr):
+		return Validation("The request has invalid fields", FieldError{
+			Field:   typeErr.Field,
+			Message: "must be " + jsonType(typeErr.Type),
+		}).Wrap(err)
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return Validation("The request body is not valid JSON").Wrap(err)
+	case errors.Is(err, io.EOF):
+		return Validation("The request body is empty").Wrap(err)
+	default:
+		return Validation("The request is invalid").Wrap(err)
+	}
+}
+
+// fieldPath returns the path of the field fe is about, such as
+// address.city, without the name of the bound struct.
+func fieldPath(fe validator.FieldError) string {
+	_, path, ok := strings.Cut(fe.Namespace(), ".")
+	if !ok {
+		return fe.Field()
+	}
+	return path
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be an email address"
+	case "url":
+		return "must be a URL"
+	case "uuid", "uuid4":
+		return "must be a UUID"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "len":
+		return "must have a length of " + fe.Param()
+	case "min", "gte":
+		if isNumber(fe.Kind()) {
+			return "must be at least " + fe.Param()
+		}
+		return "must have a length of at least " + fe.Param()
+	case "max", "lte":
+		if isNumber(fe.Kind()) {
+			return "must be at most " + fe.Param()
+		}
+		return "must have a length of at most " + fe.Param()
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	}
+	return "must satisfy " + fe.Tag()
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// jsonType names the JSON type a Go type is decoded from.
+func jsonType(t reflect.Type) string {
+	switch {
+	case t == nil:
+		return "a valid value"
+	case isNumber(t.Kind()):
+		return "a number"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "true or false"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Struct, reflect.Map:
+		return "an object"
+	}
+	return "a valid value"
+}
@@ -0,0 +1,102 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestFrom(t *testing.T) {
+	cause := errors.New("duplicate key")
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{name: "not found", err: NotFound("user %d not found", 42), status: http.StatusNotFound},
+		{name: "wrapped conflict", err: fmt.Errorf("creating user: %w", Conflict("email is taken").Wrap(cause)), status: http.StatusConflict},
+		{name: "plain error", err: cause, status: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := From(tt.err)
+			if err.Status != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, err.Status)
+			}
+			if !Is(err, tt.status) {
+				t.Errorf("Expected Is(err, %d) to be true", tt.status)
+			}
+			if problem := err.Problem("/"); problem.Title != http.StatusText(tt.status) {
+				t.Errorf("Expected title %q, got %q", http.StatusText(tt.status), problem.Title)
+			}
+		})
+	}
+}
+
+func TestFromBinding(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type signup struct {
+		Name string `json:"name" binding:"required"`
+		Age  int    `json:"age" binding:"min=18"`
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		detail string
+		fields []FieldError
+	}{
+		{
+			name:   "invalid fields",
+			body:   `{"age": 16}`,
+			detail: "The request has invalid fields",
+			fields: []FieldError{
+				{Field: "name", Message: "is required"},
+				{Field: "age", Message: "must be at least 18"},
+			},
+		},
+		{
+			name:   "wrong type",
+			body:   `{"name": "Ada", "age": "old"}`,
+			detail: "The request has invalid fields",
+			fields: []FieldError{
+				{Field: "age", Message: "must be a number"},
+			},
+		},
+		{name: "malformed", body: `{"name": `, detail: "The request body is not valid JSON"},
+		{name: "empty", body: ``, detail: "The request body is empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *Error
+			router := gin.New()
+			router.POST("/", func(c *gin.Context) {
+				var req signup
+				if err := c.ShouldBindJSON(&req); err != nil {
+					got = FromBinding(err)
+				}
+			})
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)))
+
+			if got == nil {
+				t.Fatal("Expected binding to fail")
+			}
+			if got.Status != http.StatusBadRequest || got.Detail != tt.detail {
+				t.Errorf("Expected 400 %q, got %d %q", tt.detail, got.Status, got.Detail)
+			}
+			if !reflect.DeepEqual(got.Fields, tt.fields) {
+				t.Errorf("Expected fields %v, got %v", tt.fields, got.Fields)
+			}
+		})
+	}
+}
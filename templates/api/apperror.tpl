@@ -0,0 +1,128 @@
+// Package apperror is the error model of the API. Handlers and services
+// return an *Error for every failure a client should know about, and the
+// error middleware answers with it as an RFC 7807 problem; every other
+// error becomes a 500 whose cause is only logged.
+package apperror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ContentType is the media type of problem responses.
+const ContentType = "application/problem+json"
+
+// Error is a failure with the HTTP status clients get for it. Detail is
+// shown to clients; Err, the cause, is only logged.
+type Error struct {
+	Status int
+	Detail string
+	Fields []FieldError
+	Err    error
+}
+
+// FieldError says why the value of one field of a request is invalid.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	msg := e.Detail
+	if msg == "" {
+		msg = http.StatusText(e.Status)
+	}
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap returns a copy of e caused by err.
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// New returns an error answered with status and detail.
+func New(status int, detail string) *Error {
+	return &Error{Status: status, Detail: detail}
+}
+
+// NotFound is a 404 error, such as NotFound("user %s not found", id).
+func NotFound(format string, args ...any) *Error {
+	return New(http.StatusNotFound, fmt.Sprintf(format, args...))
+}
+
+// Validation is a 400 error listing the invalid fields of a request.
+func Validation(detail string, fields ...FieldError) *Error {
+	return &Error{Status: http.StatusBadRequest, Detail: detail, Fields: fields}
+}
+
+// Conflict is a 409 error, such as Conflict("email %s is taken", email).
+func Conflict(format string, args ...any) *Error {
+	return New(http.StatusConflict, fmt.Sprintf(format, args...))
+}
+
+// Unauthorized is a 401 error for requests without valid credentials.
+func Unauthorized(detail string) *Error {
+	return New(http.StatusUnauthorized, detail)
+}
+
+// Forbidden is a 403 error for callers lacking a permission.
+func Forbidden(detail string) *Error {
+	return New(http.StatusForbidden, detail)
+}
+
+// Internal is a 500 error caused by err, which clients never see.
+func Internal(err error) *Error {
+	return &Error{Status: http.StatusInternalServerError, Err: err}
+}
+
+// From returns err as an *Error. Errors that are not an *Error are
+// internal.
+func From(err error) *Error {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return Internal(err)
+}
+
+// Is reports whether err is an *Error with status, such as
+// Is(err, http.StatusNotFound).
+func Is(err error, status int) bool {
+	var appErr *Error
+	return errors.As(err, &appErr) && appErr.Status == status
+}
+
+// Problem is an RFC 7807 problem details object. Errors and RequestID are
+// extension members.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// Problem returns the problem clients get for e. instance is the path of
+// the request that failed.
+func (e *Error) Problem(instance string) Problem {
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   e.Detail,
+		Instance: instance,
+		Errors:   e.Fields,
+	}
+}
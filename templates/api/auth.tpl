@@ -0,0 +1,240 @@
+package middleware
+
+import (
+	"context"
+{{- if eq .Auth "apikey"}}
+	"crypto/subtle"
+{{- end}}
+	"errors"
+{{- if eq .Auth "jwt-jwks"}}
+	"fmt"
+{{- end}}
+	"os"
+	"strings"
+{{- if eq .Auth "jwt-jwks"}}
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/gin-gonic/gin"
+{{- else}}
+
+	"github.com/gin-gonic/gin"
+{{- end}}
+{{- if ne .Auth "apikey"}}
+	"github.com/golang-jwt/jwt/v5"
+{{- end}}
+{{- if eq .Logger "zap"}}
+	"go.uber.org/zap"
+{{- end}}
+
+	"{{.ModuleName}}/internal/apperror"
+	"{{.ModuleName}}/internal/logging"
+)
+{{- if eq .Auth "apikey"}}
+
+// APIKeyHeader carries the API key of a request.
+const APIKeyHeader = "X-API-Key"
+
+// Claims describe the caller an API key belongs to.
+type Claims struct {
+	Subject string   `json:"sub"`
+	Roles   []string `json:"roles,omitempty"`
+}
+{{- else}}
+
+// Claims are the claims of a caller's token. Roles is read from its
+// "roles" claim.
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+{{- end}}
+
+// HasRole reports whether the caller has role.
+func (c *Claims) HasRole(role string) bool {
+	for _, r := range c.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+type claimsKey struct{}
+
+// WithClaims returns a copy of ctx carrying claims.
+func WithClaims(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// ClaimsFromContext returns the claims Auth put on the request context.
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+{{- if eq .Auth "apikey"}}
+
+// Auth returns middleware that rejects requests without a known key in
+// the X-API-Key header with 401 and puts the claims of the key on the
+// request context. API_KEYS lists the keys as comma-separated
+// name:key[:role|role...] entries; the name becomes the subject.
+func Auth(ctx context.Context) (gin.HandlerFunc, error) {
+	keys, err := parseAPIKeys(os.Getenv("API_KEYS"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			unauthorized(c, "Missing API key", nil)
+			return
+		}
+
+		var claims *Claims
+		for _, k := range keys {
+			// Compare every key so the time taken does not tell which one
+			// matched
+			if subtle.ConstantTimeCompare([]byte(key), k.key) == 1 {
+				claims = k.claims
+			}
+		}
+		if claims == nil {
+			unauthorized(c, "Invalid API key", nil)
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithClaims(c.Request.Context(), claims))
+		c.Next()
+	}, nil
+}
+
+type apiKey struct {
+	key    []byte
+	claims *Claims
+}
+
+func parseAPIKeys(s string) ([]apiKey, error) {
+	var keys []apiKey
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.New("API_KEYS entries must look like name:key or name:key:role|role")
+		}
+		claims := &Claims{Subject: parts[0]}
+		if len(parts) == 3 && parts[2] != "" {
+			claims.Roles = strings.Split(parts[2], "|")
+		}
+		keys = append(keys, apiKey{key: []byte(parts[1]), claims: claims})
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("API_KEYS must list at least one key")
+	}
+	return keys, nil
+}
+{{- else}}
+
+// Auth returns middleware that rejects requests without a valid bearer
+// token with 401 and puts the claims of the token on the request context.
+{{- if eq .Auth "jwt-jwks"}}
+// Tokens are verified with the keys published at AUTH_JWKS_URL, which are
+// refreshed in the background until ctx is done.
+{{- else}}
+// Tokens are signed with HMAC using the secret AUTH_JWT_SECRET.
+{{- end}}
+// When set, AUTH_ISSUER and AUTH_AUDIENCE must match the iss and aud
+// claims.
+func Auth(ctx context.Context) (gin.HandlerFunc, error) {
+{{- if eq .Auth "jwt-jwks"}}
+	url := os.Getenv("AUTH_JWKS_URL")
+	if url == "" {
+		return nil, errors.New("AUTH_JWKS_URL must be set")
+	}
+	jwks, err := keyfunc.NewDefaultCtx(ctx, []string{url})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the keys at %s: %v", url, err)
+	}
+	keyFunc := jwks.Keyfunc
+	methods := []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+{{- else}}
+	secret := []byte(os.Getenv("AUTH_JWT_SECRET"))
+	if len(secret) < 32 {
+		return nil, errors.New("AUTH_JWT_SECRET must be set to at least 32 bytes")
+	}
+	keyFunc := func(*jwt.Token) (interface{}, error) {
+		return secret, nil
+	}
+	methods := []string{"HS256", "HS384", "HS512"}
+{{- end}}
+
+	// Accept only the expected algorithms, so a token cannot choose how it
+	// is verified
+	opts := []jwt.ParserOption{jwt.WithValidMethods(methods), jwt.WithExpirationRequired()}
+	if issuer := os.Getenv("AUTH_ISSUER"); issuer != "" {
+		opts = append(opts, jwt.WithIssuer(issuer))
+	}
+	if audience := os.Getenv("AUTH_AUDIENCE"); audience != "" {
+		opts = append(opts, jwt.WithAudience(audience))
+	}
+	parser := jwt.NewParser(opts...)
+
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			unauthorized(c, "Missing bearer token", nil)
+			return
+		}
+
+		claims := &Claims{}
+		if _, err := parser.ParseWithClaims(token, claims, keyFunc); err != nil {
+			unauthorized(c, "Invalid bearer token", err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithClaims(c.Request.Context(), claims))
+		c.Next()
+	}, nil
+}
+{{- end}}
+
+// RequireRole returns middleware that lets a request through only if the
+// caller Auth authenticated has at least one of roles, and rejects it with
+// 403 otherwise. Use it after Auth.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := ClaimsFromContext(c.Request.Context())
+		if !ok {
+			unauthorized(c, "Not authenticated", errors.New("RequireRole used without Auth"))
+			return
+		}
+		for _, role := range roles {
+			if claims.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+		AbortWithError(c, apperror.Forbidden("Missing role: needs one of "+strings.Join(roles, ", ")))
+	}
+}
+
+// unauthorized rejects the request with 401. The reason err is only logged,
+// so clients cannot probe why their credentials failed.
+func unauthorized(c *gin.Context, message string, err error) {
+	if err != nil {
+{{- if eq .Logger "zap"}}
+		logging.FromContext(c.Request.Context()).Debug("request rejected", zap.Error(err))
+{{- else if eq .Logger "zerolog"}}
+		logging.FromContext(c.Request.Context()).Debug().Err(err).Msg("request rejected")
+{{- else}}
+		logging.FromContext(c.Request.Context()).Debug("request rejected", "error", err)
+{{- end}}
+	}
+{{- if ne .Auth "apikey"}}
+	c.Header("WWW-Authenticate", "Bearer")
+{{- end}}
+	AbortWithError(c, apperror.Unauthorized(message))
+}
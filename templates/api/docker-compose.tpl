@@ -7,6 +7,11 @@ services:
       - POSTGRES_USER=postgres
       - POSTGRES_PASSWORD=postgres
       - POSTGRES_DB={{.ProjectName}}
+    healthcheck:
+      test: ["CMD", "pg_isready", "-U", "postgres", "-d", "{{.ProjectName}}"]
+      interval: 2s
+      timeout: 5s
+      retries: 15
     volumes:
       - postgres_data:/var/lib/postgresql/data{{end}}
 
@@ -28,6 +33,33 @@ services:
     volumes:
       - rabbitmq_data:/var/lib/rabbitmq{{end}}
 
+  {{if .UseMetrics}}prometheus:
+    image: prom/prometheus:latest
+    ports:
+      - "9090:9090"
+    volumes:
+      - ./prometheus.yml:/etc/prometheus/prometheus.yml:ro
+    extra_hosts:
+      - "host.docker.internal:host-gateway"{{end}}
+
+  {{if .UseTracing}}otel-collector:
+    image: otel/opentelemetry-collector-contrib:latest
+    command: ["--config=/etc/otelcol/config.yaml"]
+    volumes:
+      - ./otel-collector.yaml:/etc/otelcol/config.yaml:ro
+    ports:
+      - "4317:4317"
+      - "4318:4318"
+    depends_on:
+      - jaeger
+
+  jaeger:
+    image: jaegertracing/all-in-one:latest
+    environment:
+      - COLLECTOR_OTLP_ENABLED=true
+    ports:
+      - "16686:16686"{{end}}
+
 volumes:
   {{if .UsePostgres}}postgres_data:{{end}}
   {{if .UseRedis}}redis_data:{{end}}
@@ -1,5 +1,5 @@
 # Build stage
-FROM golang:1.21-alpine AS builder
+FROM golang:{{.GoImageVersion}}-alpine AS builder
 
 WORKDIR /app
 
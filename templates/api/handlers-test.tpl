@@ -0,0 +1,137 @@
+package handlers_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"{{.ModuleName}}/internal/apperror"
+	"{{.ModuleName}}/internal/health"
+	"{{.ModuleName}}/internal/testutil"
+)
+
+func TestPing(t *testing.T) {
+	router := testutil.NewRouter(t, testutil.Dependencies(t, testutil.NewHandlers(health.NewRegistry())))
+
+	rec := testutil.Do(t, router, http.MethodGet, "/api/ping", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var body struct {
+		Message string `json:"message"`
+	}
+	testutil.DecodeJSON(t, rec, &body)
+	if body.Message != "pong" {
+		t.Errorf("Expected message pong, got %q", body.Message)
+	}
+}
+
+func TestLivez(t *testing.T) {
+	// A failing dependency does not make the service dead
+	checks := health.NewRegistry()
+	checks.Register("database", time.Second, func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	router := testutil.NewRouter(t, testutil.Dependencies(t, testutil.NewHandlers(checks)))
+
+	rec := testutil.Do(t, router, http.MethodGet, "/livez", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var body struct {
+		Status string `json:"status"`
+	}
+	testutil.DecodeJSON(t, rec, &body)
+	if body.Status != health.StatusOK {
+		t.Errorf("Expected status %s, got %q", health.StatusOK, body.Status)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  health.CheckFunc
+		status int
+		report string
+	}{
+		{
+			name:   "ready",
+			check:  func(ctx context.Context) error { return nil },
+			status: http.StatusOK,
+			report: health.StatusOK,
+		},
+		{
+			name:   "dependency down",
+			check:  func(ctx context.Context) error { return errors.New("connection refused") },
+			status: http.StatusServiceUnavailable,
+			report: health.StatusFail,
+		},
+		{
+			name: "dependency too slow",
+			check: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			status: http.StatusServiceUnavailable,
+			report: health.StatusFail,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checks := health.NewRegistry()
+			checks.Register("database", 50*time.Millisecond, tt.check)
+			router := testutil.NewRouter(t, testutil.Dependencies(t, testutil.NewHandlers(checks)))
+
+			rec := testutil.Do(t, router, http.MethodGet, "/readyz", nil)
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			var report health.Report
+			testutil.DecodeJSON(t, rec, &report)
+			if report.Status != tt.report || report.Checks["database"].Status != tt.report {
+				t.Errorf("Expected report status %s, got %+v", tt.report, report)
+			}
+		})
+	}
+}
+
+func TestNotFound(t *testing.T) {
+	router := testutil.NewRouter(t, testutil.Dependencies(t, testutil.NewHandlers(health.NewRegistry())))
+
+	rec := testutil.Do(t, router, http.MethodGet, "/nowhere", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+	if got := rec.Header().Get("Content-Type"); got != apperror.ContentType {
+		t.Errorf("Expected Content-Type %s, got %s", apperror.ContentType, got)
+	}
+	var problem apperror.Problem
+	testutil.DecodeJSON(t, rec, &problem)
+	if problem.Status != http.StatusNotFound || problem.Instance != "/nowhere" {
+		t.Errorf("Unexpected problem %+v", problem)
+	}
+}
+{{- if ne .Auth "none"}}
+
+func TestMe(t *testing.T) {
+	deps := testutil.Dependencies(t, testutil.NewHandlers(health.NewRegistry()))
+	deps.Auth = testutil.Authenticate(testutil.Caller("alice", "admin"))
+	router := testutil.NewRouter(t, deps)
+
+	rec := testutil.Do(t, router, http.MethodGet, "/api/me", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+	var body struct {
+		Subject string   `json:"subject"`
+		Roles   []string `json:"roles"`
+	}
+	testutil.DecodeJSON(t, rec, &body)
+	if body.Subject != "alice" || len(body.Roles) != 1 || body.Roles[0] != "admin" {
+		t.Errorf("Expected alice with the admin role, got %+v", body)
+	}
+}
+{{- end}}
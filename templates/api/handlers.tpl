@@ -1,3 +1,5 @@
+// Package handlers holds the HTTP handlers of the application. They
+// decode requests, call the services and encode their results.
 package handlers
 
 import (
@@ -5,28 +7,89 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"{{.ModuleName}}/internal/apperror"
+	"{{.ModuleName}}/internal/health"
+	"{{.ModuleName}}/internal/logging"
+{{- if ne .Auth "none"}}
+	"{{.ModuleName}}/internal/middleware"
+{{- end}}
+	"{{.ModuleName}}/internal/service"
+{{- if .UseTracing}}
+	"{{.ModuleName}}/internal/tracing"
+{{- end}}
 )
 
-// HealthHandler returns a 200 OK response if the service is healthy
-func HealthHandler(c *gin.Context) {
+// Handlers holds the handlers of the application and what they depend
+// on.
+type Handlers struct {
+	services *service.Services
+	checks   *health.Registry
+}
+
+// New returns the handlers of the application. Readyz runs the checks of
+// checks.
+func New(services *service.Services, checks *health.Registry) *Handlers {
+	return &Handlers{services: services, checks: checks}
+}
+
+// Livez returns 200 OK while the process can serve requests. It checks no
+// dependencies, so a liveness probe does not restart the service when its
+// database is down.
+func (h *Handlers) Livez(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
-		"status":    "ok",
+		"status":    health.StatusOK,
 		"service":   "{{.ProjectName}}",
 		"timestamp": time.Now().Format(time.RFC3339),
 	})
 }
 
-// PingHandler returns a simple pong response
-func PingHandler(c *gin.Context) {
+// Readyz runs the registered health checks and returns 200 OK if every
+// dependency is ready, 503 Service Unavailable otherwise, with the status
+// and latency of each check.
+func (h *Handlers) Readyz(c *gin.Context) {
+	report := h.checks.Check(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
+
+// Ping returns a simple pong response
+func (h *Handlers) Ping(c *gin.Context) {
+{{- if .UseTracing}}
+	// Pass ctx on to services so their spans join the request's trace
+	ctx, span := tracing.Start(c.Request.Context(), "Handlers.Ping")
+	defer span.End()
+{{- else}}
+	ctx := c.Request.Context()
+{{- end}}
+{{- if eq .Logger "zerolog"}}
+	logging.FromContext(ctx).Debug().Msg("ping")
+{{- else}}
+	logging.FromContext(ctx).Debug("ping")
+{{- end}}
 	c.JSON(http.StatusOK, gin.H{
-		"message": "pong",
+		"message": h.services.Ping(ctx),
 	})
 }
 
+{{- if ne .Auth "none"}}
 
-// NotFoundHandler handles 404 errors
-func NotFoundHandler(c *gin.Context) {
-	c.JSON(http.StatusNotFound, gin.H{
-		"error": "Resource not found",
+// Me returns the subject and roles of the authenticated caller
+func (h *Handlers) Me(c *gin.Context) {
+	claims, _ := middleware.ClaimsFromContext(c.Request.Context())
+	c.JSON(http.StatusOK, gin.H{
+		"subject": claims.Subject,
+		"roles":   claims.Roles,
 	})
 }
+{{- end}}
+
+// NotFound answers requests matching no route with 404. Like every
+// handler, it fails by adding an apperror to the context, which the error
+// middleware turns into a problem+json response.
+func (h *Handlers) NotFound(c *gin.Context) {
+	_ = c.Error(apperror.NotFound("No route for %s %s", c.Request.Method, c.Request.URL.Path))
+}
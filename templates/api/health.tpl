@@ -0,0 +1,118 @@
+// Package health runs readiness checks, one per dependency the service
+// needs, such as its database.
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status values of a report and of each of its checks.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckFunc reports whether a dependency can serve requests. It should
+// return once ctx is done.
+type CheckFunc func(ctx context.Context) error
+
+type check struct {
+	name    string
+	timeout time.Duration
+	fn      CheckFunc
+}
+
+// Registry holds the checks of the dependencies the application connected
+// to. It is created once and passed to what registers and runs checks.
+type Registry struct {
+	mu     sync.RWMutex
+	checks []check
+}
+
+// NewRegistry returns a registry without checks.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a check named name. A check that runs longer than timeout
+// fails.
+func (r *Registry) Register(name string, timeout time.Duration, fn CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks = append(r.checks, check{name: name, timeout: timeout, fn: fn})
+}
+
+// Result is the outcome of one check.
+type Result struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report is the outcome of every registered check. Its Status is ok when
+// every check passed.
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Check runs the registered checks concurrently, each with its own
+// timeout.
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	registered := make([]check, len(r.checks))
+	copy(registered, r.checks)
+	r.mu.RUnlock()
+
+	results := make([]Result, len(registered))
+	var wg sync.WaitGroup
+	for i, c := range registered {
+		wg.Add(1)
+		go func(i int, c check) {
+			defer wg.Done()
+			results[i] = run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(registered))}
+	for i, c := range registered {
+		if results[i].Status != StatusOK {
+			report.Status = StatusFail
+		}
+		report.Checks[c.name] = results[i]
+	}
+	return report
+}
+
+func run(ctx context.Context, c check) Result {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- c.fn(ctx)
+	}()
+
+	// Checks that ignore ctx still fail on time
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("timed out after %s", c.timeout)
+	}
+
+	result := Result{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+// Package logging builds the application logger and carries request-scoped
+// loggers on request contexts.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Logger is the logger type handlers and middleware receive.
+type Logger = *slog.Logger
+
+type contextKey struct{}
+
+// New returns a logger writing to stderr at level (debug, info, warn or
+// error) in format (text or json).
+func New(level, format string) (Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid log format %q: use text or json", format)
+	}
+}
+
+// FromEnv returns a logger configured by LOG_LEVEL (default info) and
+// LOG_FORMAT (default json).
+func FromEnv() (Logger, error) {
+	return New(getenv("LOG_LEVEL", "info"), getenv("LOG_FORMAT", "json"))
+}
+
+// WithRequestID returns a logger that tags every entry with requestID.
+func WithRequestID(logger Logger, requestID string) Logger {
+	return logger.With("request_id", requestID)
+}
+
+// WithContext returns a copy of ctx that carries logger.
+func WithContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or slog.Default().
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
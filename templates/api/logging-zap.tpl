@@ -0,0 +1,71 @@
+// Package logging builds the application logger and carries request-scoped
+// loggers on request contexts.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Logger is the logger type handlers and middleware receive.
+type Logger = *zap.Logger
+
+type contextKey struct{}
+
+// New returns a logger writing to stderr at level (debug, info, warn or
+// error) in format (text or json).
+func New(level, format string) (Logger, error) {
+	lvl, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(lvl)
+	cfg.OutputPaths = []string{"stderr"}
+	switch format {
+	case "text":
+		cfg.Encoding = "console"
+		cfg.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	case "json":
+		cfg.Encoding = "json"
+	default:
+		return nil, fmt.Errorf("invalid log format %q: use text or json", format)
+	}
+	return cfg.Build()
+}
+
+// FromEnv returns a logger configured by LOG_LEVEL (default info) and
+// LOG_FORMAT (default json).
+func FromEnv() (Logger, error) {
+	return New(getenv("LOG_LEVEL", "info"), getenv("LOG_FORMAT", "json"))
+}
+
+// WithRequestID returns a logger that tags every entry with requestID.
+func WithRequestID(logger Logger, requestID string) Logger {
+	return logger.With(zap.String("request_id", requestID))
+}
+
+// WithContext returns a copy of ctx that carries logger.
+func WithContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or a no-op logger.
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return logger
+	}
+	return zap.NewNop()
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
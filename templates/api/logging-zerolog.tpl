@@ -0,0 +1,72 @@
+// Package logging builds the application logger and carries request-scoped
+// loggers on request contexts.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+// Logger is the logger type handlers and middleware receive.
+type Logger = *zerolog.Logger
+
+type contextKey struct{}
+
+// New returns a logger writing to stderr at level (debug, info, warn or
+// error) in format (text or json).
+func New(level, format string) (Logger, error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	var w io.Writer
+	switch format {
+	case "text":
+		w = zerolog.ConsoleWriter{Out: os.Stderr}
+	case "json":
+		w = os.Stderr
+	default:
+		return nil, fmt.Errorf("invalid log format %q: use text or json", format)
+	}
+
+	logger := zerolog.New(w).Level(lvl).With().Timestamp().Logger()
+	return &logger, nil
+}
+
+// FromEnv returns a logger configured by LOG_LEVEL (default info) and
+// LOG_FORMAT (default json).
+func FromEnv() (Logger, error) {
+	return New(getenv("LOG_LEVEL", "info"), getenv("LOG_FORMAT", "json"))
+}
+
+// WithRequestID returns a logger that tags every entry with requestID.
+func WithRequestID(logger Logger, requestID string) Logger {
+	tagged := logger.With().Str("request_id", requestID).Logger()
+	return &tagged
+}
+
+// WithContext returns a copy of ctx that carries logger.
+func WithContext(ctx context.Context, logger Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or a disabled logger.
+func FromContext(ctx context.Context) Logger {
+	if logger, ok := ctx.Value(contextKey{}).(Logger); ok {
+		return logger
+	}
+	nop := zerolog.Nop()
+	return &nop
+}
+
+func getenv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -1,37 +1,86 @@
 package middleware
 
 import (
+{{- if not .UseMiddleware.requestid}}
+	"crypto/rand"
+	"encoding/hex"
+{{- end}}
 	"time"
-	"go.uber.org/zap"
+
 	"github.com/gin-gonic/gin"
+{{- if eq .Logger "zap"}}
+	"go.uber.org/zap"
+{{- end}}
+
+	"{{.ModuleName}}/internal/logging"
 )
 
-var logger *zap.Logger
+{{- if .UseMiddleware.requestid}}
 
-func init() {
-	var err error
-	logger, err = zap.NewProduction()
-	if err != nil {
-		panic(err)
-	}
-}
+// RequestLogger puts a logger tagged with the ID RequestID gave the request
+// on the request context and logs the request once it completes. Use it
+// after RequestID.
+func RequestLogger(logger logging.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := GetRequestID(c)
+{{- else}}
 
-func LoggingMiddleware() gin.HandlerFunc {
+// RequestIDHeader carries the ID of a request in both directions.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestLogger gives every request an ID, taken from the X-Request-ID
+// header when the client sends one, puts a logger tagged with it on the
+// request context and logs the request once it completes.
+func RequestLogger(logger logging.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
-		path := c.Request.URL.Path
-		method := c.Request.Method
+
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" {
+			requestID = newRequestID()
+		}
+		c.Header(RequestIDHeader, requestID)
+{{- end}}
+
+		reqLogger := logging.WithRequestID(logger, requestID)
+		c.Request = c.Request.WithContext(logging.WithContext(c.Request.Context(), reqLogger))
 
 		c.Next()
 
-		latency := time.Since(start)
-		status := c.Writer.Status()
+{{- if eq .Logger "zap"}}
 
-		logger.Info("request completed",
-			zap.String("path", path),
-			zap.String("method", method),
-			zap.Int("status", status),
-			zap.Duration("latency", latency),
+		reqLogger.Info("request completed",
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.Int("status", c.Writer.Status()),
+			zap.Duration("latency", time.Since(start)),
 		)
+{{- else if eq .Logger "zerolog"}}
+
+		reqLogger.Info().
+			Str("method", c.Request.Method).
+			Str("path", c.Request.URL.Path).
+			Int("status", c.Writer.Status()).
+			Dur("latency", time.Since(start)).
+			Msg("request completed")
+{{- else}}
+
+		reqLogger.Info("request completed",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"latency", time.Since(start),
+		)
+{{- end}}
 	}
-} 
\ No newline at end of file
+}
+{{- if not .UseMiddleware.requestid}}
+
+func newRequestID() string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+{{- end}}
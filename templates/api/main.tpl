@@ -1,10 +1,23 @@
 package main
 
 import (
+{{- if or .UseTracing (ne .DI "fx")}}
+	"context"
+{{- end}}
 	"log"
-	"{{.ModuleName}}/internal/server"
-	"{{.ModuleName}}/internal/service"
+{{- if ne .DI "fx"}}
+	"os"
+	"os/signal"
+	"syscall"
+{{- end}}
+
 	"github.com/joho/godotenv"
+
+	"{{.ModuleName}}/internal/app"
+	"{{.ModuleName}}/internal/logging"
+{{- if .UseTracing}}
+	"{{.ModuleName}}/internal/tracing"
+{{- end}}
 )
 
 func main() {
@@ -13,15 +26,45 @@ func main() {
 		log.Printf("Warning: .env file not found")
 	}
 
-	// Initialize all services
-	if err := service.InitServices(); err != nil {
-		log.Fatalf("Failed to initialize services: %v", err)
+	// LOG_LEVEL and LOG_FORMAT configure the logger
+	logger, err := logging.FromEnv()
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+{{- if eq .Logger "zap"}}
+	defer logger.Sync()
+{{- end}}
+
+{{- if .UseTracing}}
+
+	// OTEL_* variables configure the exporter; see internal/tracing
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer shutdownTracing(context.Background())
+{{- end}}
+{{- if eq .DI "fx"}}
+
+	// Build the application and serve until SIGINT or SIGTERM; see
+	// internal/app
+	app.New(logger).Run()
+{{- else}}
+
+	// Serve until SIGINT or SIGTERM, then let the requests in flight finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Connect to the dependencies and build the application; see
+	// internal/app
+	application, err := app.New(ctx, logger)
+	if err != nil {
+		log.Fatalf("Failed to initialize the application: %v", err)
 	}
-	defer service.CloseServices()
+	defer application.Close()
 
-	// Create and start server
-	srv := server.NewServer()
-	if err := srv.Start(); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	if err := application.Run(ctx); err != nil {
+		log.Fatalf("Failed to run the server: %v", err)
 	}
-} 
\ No newline at end of file
+{{- end}}
+}
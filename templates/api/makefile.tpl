@@ -0,0 +1,19 @@
+.PHONY: build run test test-integration
+
+build:
+	go build -o {{.ProjectName}} ./cmd
+
+run:
+	go run ./cmd
+
+# Unit tests, which need no other service
+test:
+	go test ./...
+
+# Integration tests, built with the integration tag{{if .UsePostgres}}, against the
+# PostgreSQL of docker-compose.yml{{end}}
+test-integration:
+{{- if .UsePostgres}}
+	docker compose up -d --wait postgres
+{{- end}}
+	go test -tags integration -count=1 ./...
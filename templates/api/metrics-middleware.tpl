@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"{{.ModuleName}}/internal/metrics"
+)
+
+// Metrics records the count, latency and in-flight number of requests.
+// Requests are labeled by route template, such as /api/users/:id, so IDs
+// in paths do not create a time series each; requests matching no route
+// are labeled "unmatched".
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+
+		inFlight := metrics.RequestsInFlight.WithLabelValues(method, route)
+		inFlight.Inc()
+		// Deferred so a panic unwinding to Recovery still releases it.
+		defer inFlight.Dec()
+		start := time.Now()
+
+		c.Next()
+
+		metrics.RequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		metrics.RequestsTotal.WithLabelValues(method, route, strconv.Itoa(c.Writer.Status())).Inc()
+	}
+}
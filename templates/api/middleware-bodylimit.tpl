@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"{{.ModuleName}}/internal/apperror"
+)
+
+// DefaultMaxBodyBytes is the body size limit when MAX_BODY_BYTES is unset.
+const DefaultMaxBodyBytes = 1 << 20
+
+// MaxBodyBytesFromEnv returns the body size limit set by MAX_BODY_BYTES, or
+// DefaultMaxBodyBytes.
+func MaxBodyBytesFromEnv() (int64, error) {
+	s := os.Getenv("MAX_BODY_BYTES")
+	if s == "" {
+		return DefaultMaxBodyBytes, nil
+	}
+	limit, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || limit <= 0 {
+		return 0, errors.New("MAX_BODY_BYTES must be a positive number of bytes")
+	}
+	return limit, nil
+}
+
+// BodyLimit rejects requests declaring a body larger than maxBytes with
+// 413, and makes reading past maxBytes of any other body fail, so a client
+// cannot make a handler buffer an unbounded body.
+func BodyLimit(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			AbortWithError(c, apperror.New(http.StatusRequestEntityTooLarge,
+				fmt.Sprintf("The request body is larger than %d bytes", maxBytes)))
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes)
+		}
+		c.Next()
+	}
+}
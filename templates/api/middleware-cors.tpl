@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig says which cross-origin requests browsers may make.
+type CORSConfig struct {
+	// AllowedOrigins lists the origins allowed to call the API, such as
+	// https://app.example.com; "*" allows every origin.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache the answer to a preflight.
+	MaxAge time.Duration
+}
+
+// CORSConfigFromEnv reads the CORS configuration from
+// CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS and CORS_ALLOWED_HEADERS,
+// comma-separated lists, CORS_ALLOW_CREDENTIALS (default false) and
+// CORS_MAX_AGE (default 10m). Without allowed origins, no cross-origin
+// request is allowed.
+func CORSConfigFromEnv() (CORSConfig, error) {
+	cfg := CORSConfig{
+		AllowedOrigins: splitList(os.Getenv("CORS_ALLOWED_ORIGINS")),
+		AllowedMethods: splitList(os.Getenv("CORS_ALLOWED_METHODS")),
+		AllowedHeaders: splitList(os.Getenv("CORS_ALLOWED_HEADERS")),
+		MaxAge:         10 * time.Minute,
+	}
+	if len(cfg.AllowedMethods) == 0 {
+		cfg.AllowedMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE"}
+	}
+	if len(cfg.AllowedHeaders) == 0 {
+		cfg.AllowedHeaders = []string{"Authorization", "Content-Type", RequestIDHeader}
+	}
+
+	if s := os.Getenv("CORS_ALLOW_CREDENTIALS"); s != "" {
+		allow, err := strconv.ParseBool(s)
+		if err != nil {
+			return CORSConfig{}, errors.New("CORS_ALLOW_CREDENTIALS must be true or false")
+		}
+		cfg.AllowCredentials = allow
+	}
+	if s := os.Getenv("CORS_MAX_AGE"); s != "" {
+		maxAge, err := time.ParseDuration(s)
+		if err != nil || maxAge < 0 {
+			return CORSConfig{}, errors.New("CORS_MAX_AGE must be a duration such as 10m")
+		}
+		cfg.MaxAge = maxAge
+	}
+
+	if cfg.AllowCredentials {
+		for _, origin := range cfg.AllowedOrigins {
+			if origin == "*" {
+				return CORSConfig{}, errors.New("CORS_ALLOW_CREDENTIALS cannot be used with every origin: list the allowed origins")
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// CORS returns middleware that lets browsers call the API from the origins
+// cfg allows. Preflight requests are answered directly: with 204 for
+// allowed origins and 403 for the others.
+func CORS(cfg CORSConfig) gin.HandlerFunc {
+	origins := map[string]bool{}
+	anyOrigin := false
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			anyOrigin = true
+		}
+		origins[origin] = true
+	}
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		c.Writer.Header().Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !anyOrigin && !origins[origin] {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if anyOrigin && !cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+
+		if preflight {
+			c.Header("Access-Control-Allow-Methods", methods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			c.Header("Access-Control-Max-Age", maxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Header("Access-Control-Expose-Headers", RequestIDHeader)
+		c.Next()
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty entries.
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"{{.ModuleName}}/internal/apperror"
+)
+
+func TestErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Errors())
+	router.GET("/users/:id", func(c *gin.Context) {
+		_ = c.Error(apperror.NotFound("user %s not found", c.Param("id")))
+	})
+	router.GET("/internal", func(c *gin.Context) {
+		_ = c.Error(errors.New("connection refused"))
+	})
+	router.GET("/rejected", func(c *gin.Context) {
+		AbortWithError(c, apperror.Conflict("already done"))
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		detail string
+	}{
+		{path: "/users/42", status: http.StatusNotFound, detail: "user 42 not found"},
+		{path: "/internal", status: http.StatusInternalServerError, detail: ""},
+		{path: "/rejected", status: http.StatusConflict, detail: "already done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != apperror.ContentType {
+				t.Errorf("Expected Content-Type %s, got %s", apperror.ContentType, got)
+			}
+
+			var problem apperror.Problem
+			if err := json.Unmarshal(rec.Body.Bytes(), &problem); err != nil {
+				t.Fatalf("Failed to decode problem: %v", err)
+			}
+			if problem.Status != tt.status || problem.Detail != tt.detail || problem.Instance != tt.path {
+				t.Errorf("Unexpected problem %+v", problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"encoding/json"
+
+	"github.com/gin-gonic/gin"
+{{- if eq .Logger "zap"}}
+	"go.uber.org/zap"
+{{- end}}
+
+	"{{.ModuleName}}/internal/apperror"
+	"{{.ModuleName}}/internal/logging"
+)
+
+// Errors answers a request whose handler failed with c.Error and wrote
+// nothing with the RFC 7807 problem of the last error; see package
+// apperror. Internal errors are logged with their cause, which clients
+// never see. Use it after RequestLogger.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		err := apperror.From(c.Errors.Last().Err)
+		if err.Status >= 500 {
+{{- if eq .Logger "zap"}}
+			logging.FromContext(c.Request.Context()).Error("request failed", zap.Error(err))
+{{- else if eq .Logger "zerolog"}}
+			logging.FromContext(c.Request.Context()).Error().Err(err).Msg("request failed")
+{{- else}}
+			logging.FromContext(c.Request.Context()).Error("request failed", "error", err)
+{{- end}}
+		}
+		if !c.Writer.Written() {
+			writeProblem(c, err)
+		}
+	}
+}
+
+// AbortWithError stops the request and answers it with the problem of
+// err right away, for middleware rejecting requests.
+func AbortWithError(c *gin.Context, err error) {
+	c.Abort()
+	writeProblem(c, apperror.From(err))
+}
+
+func writeProblem(c *gin.Context, err *apperror.Error) {
+	problem := err.Problem(c.Request.URL.Path)
+	problem.RequestID = c.Writer.Header().Get(RequestIDHeader)
+	body, _ := json.Marshal(problem)
+	c.Data(err.Status, apperror.ContentType, body)
+}
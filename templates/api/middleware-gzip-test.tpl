@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGzip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Gzip())
+	router.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"message": "hello"})
+	})
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		gzipped        bool
+	}{
+		{name: "gzip accepted", acceptEncoding: "br, gzip", gzipped: true},
+		{name: "gzip refused", acceptEncoding: "gzip;q=0", gzipped: false},
+		{name: "no encoding", acceptEncoding: "", gzipped: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			gzipped := rec.Header().Get("Content-Encoding") == "gzip"
+			if gzipped != tt.gzipped {
+				t.Fatalf("Expected gzipped %v, got Content-Encoding %q", tt.gzipped, rec.Header().Get("Content-Encoding"))
+			}
+
+			var body io.Reader = rec.Body
+			if gzipped {
+				zr, err := gzip.NewReader(rec.Body)
+				if err != nil {
+					t.Fatalf("Failed to read gzipped body: %v", err)
+				}
+				body = zr
+			}
+			data, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("Failed to read body: %v", err)
+			}
+			if !strings.Contains(string(data), "hello") {
+				t.Errorf("Expected the body to contain the message, got %q", data)
+			}
+		})
+	}
+}
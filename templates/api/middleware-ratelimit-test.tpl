@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(RateLimit(NewMemoryLimiter(2, time.Minute)))
+	router.GET("/", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		client string
+		status int
+	}{
+		{client: "192.0.2.1", status: http.StatusOK},
+		{client: "192.0.2.1", status: http.StatusOK},
+		{client: "192.0.2.1", status: http.StatusTooManyRequests},
+		{client: "192.0.2.2", status: http.StatusOK},
+	}
+
+	for i, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = tt.client + ":1234"
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Errorf("Request %d from %s: expected status %d, got %d", i+1, tt.client, tt.status, rec.Code)
+		}
+		if tt.status == http.StatusTooManyRequests && rec.Header().Get("Retry-After") == "" {
+			t.Errorf("Request %d from %s: expected a Retry-After header", i+1, tt.client)
+		}
+	}
+}
+
+func TestMemoryLimiterWindow(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewMemoryLimiter(1, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	allow := func() bool {
+		allowed, _, err := limiter.Allow(context.Background(), "client")
+		if err != nil {
+			t.Fatalf("Failed to count request: %v", err)
+		}
+		return allowed
+	}
+
+	if !allow() {
+		t.Fatal("Expected the first request to be allowed")
+	}
+	if allow() {
+		t.Fatal("Expected the second request in the window to be rejected")
+	}
+	now = now.Add(time.Minute)
+	if !allow() {
+		t.Error("Expected a request in the next window to be allowed")
+	}
+}
@@ -0,0 +1,173 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+{{- if .UseRedis}}
+	"github.com/redis/go-redis/v9"
+{{- end}}
+{{- if eq .Logger "zap"}}
+	"go.uber.org/zap"
+{{- end}}
+
+	"{{.ModuleName}}/internal/apperror"
+	"{{.ModuleName}}/internal/logging"
+)
+
+// Limiter counts the requests of clients in fixed windows.
+type Limiter interface {
+	// Allow counts a request of client and reports whether it is within
+	// the limit, and how long until the client's window ends.
+	Allow(ctx context.Context, client string) (bool, time.Duration, error)
+}
+
+// LimiterFromEnv returns a limiter allowing RATE_LIMIT_REQUESTS requests
+// (default 100) per client every RATE_LIMIT_WINDOW (default 1m).
+{{- if .UseRedis}}
+// Counts are kept in Redis, so every instance of the API shares them.
+func LimiterFromEnv(client *redis.Client) (Limiter, error) {
+{{- else}}
+func LimiterFromEnv() (Limiter, error) {
+{{- end}}
+	limit := 100
+	if s := os.Getenv("RATE_LIMIT_REQUESTS"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			return nil, errors.New("RATE_LIMIT_REQUESTS must be a positive number")
+		}
+		limit = n
+	}
+	window := time.Minute
+	if s := os.Getenv("RATE_LIMIT_WINDOW"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil || d <= 0 {
+			return nil, errors.New("RATE_LIMIT_WINDOW must be a duration such as 1m")
+		}
+		window = d
+	}
+{{- if .UseRedis}}
+	return NewRedisLimiter(client, limit, window), nil
+{{- else}}
+	return NewMemoryLimiter(limit, window), nil
+{{- end}}
+}
+
+// RateLimit rejects the requests of a client, identified by its IP, over
+// the limit of limiter with 429 and a Retry-After header. Requests are let
+// through when the limiter fails, so an outage of its store does not take
+// the API down.
+func RateLimit(limiter Limiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, retryAfter, err := limiter.Allow(c.Request.Context(), c.ClientIP())
+		if err != nil {
+{{- if eq .Logger "zap"}}
+			logging.FromContext(c.Request.Context()).Warn("rate limiter failed", zap.Error(err))
+{{- else if eq .Logger "zerolog"}}
+			logging.FromContext(c.Request.Context()).Warn().Err(err).Msg("rate limiter failed")
+{{- else}}
+			logging.FromContext(c.Request.Context()).Warn("rate limiter failed", "error", err)
+{{- end}}
+			c.Next()
+			return
+		}
+		if !allowed {
+			seconds := int((retryAfter + time.Second - 1) / time.Second)
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			AbortWithError(c, apperror.New(http.StatusTooManyRequests,
+				fmt.Sprintf("Too many requests: retry in %d seconds", seconds)))
+			return
+		}
+		c.Next()
+	}
+}
+
+// MemoryLimiter keeps the counts of a single instance in memory.
+type MemoryLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	windows   map[string]*clientWindow
+	lastSweep time.Time
+}
+
+type clientWindow struct {
+	start time.Time
+	count int
+}
+
+// NewMemoryLimiter returns a limiter allowing limit requests per client
+// every window.
+func NewMemoryLimiter(limit int, window time.Duration) *MemoryLimiter {
+	return &MemoryLimiter{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		windows: map[string]*clientWindow{},
+	}
+}
+
+func (l *MemoryLimiter) Allow(ctx context.Context, client string) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	// Forget the clients whose window ended, once per window
+	if now.Sub(l.lastSweep) >= l.window {
+		for key, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &clientWindow{start: now}
+		l.windows[client] = w
+	}
+	w.count++
+	return w.count <= l.limit, w.start.Add(l.window).Sub(now), nil
+}
+{{- if .UseRedis}}
+
+// RedisLimiter keeps the counts in Redis, shared by every instance.
+type RedisLimiter struct {
+	client *redis.Client
+	limit  int
+	window time.Duration
+}
+
+// NewRedisLimiter returns a limiter allowing limit requests per client
+// every window.
+func NewRedisLimiter(client *redis.Client, limit int, window time.Duration) *RedisLimiter {
+	return &RedisLimiter{client: client, limit: limit, window: window}
+}
+
+func (l *RedisLimiter) Allow(ctx context.Context, client string) (bool, time.Duration, error) {
+	now := time.Now()
+	start := now.Truncate(l.window)
+	key := fmt.Sprintf("ratelimit:%s:%d", client, start.Unix())
+
+	count, err := l.client.Incr(ctx, key).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	if count == 1 {
+		if err := l.client.Expire(ctx, key, l.window).Err(); err != nil {
+			return false, 0, err
+		}
+	}
+	return count <= int64(l.limit), start.Add(l.window).Sub(now), nil
+}
+{{- end}}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+{{- if eq .Logger "zap"}}
+	"go.uber.org/zap"
+{{- end}}
+
+	"{{.ModuleName}}/internal/apperror"
+	"{{.ModuleName}}/internal/logging"
+)
+
+// Recovery turns a panic in a later handler into a 500 problem response and
+// logs it with its stack through the request's logger. Use it after
+// RequestLogger, so the entry carries the request ID.
+func Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			err := recover()
+			if err == nil {
+				return
+			}
+			// net/http aborts the response quietly on ErrAbortHandler
+			if err == http.ErrAbortHandler {
+				panic(err)
+			}
+
+{{- if eq .Logger "zap"}}
+
+			logging.FromContext(c.Request.Context()).Error("panic recovered",
+				zap.Any("panic", err),
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.ByteString("stack", debug.Stack()),
+			)
+{{- else if eq .Logger "zerolog"}}
+
+			logging.FromContext(c.Request.Context()).Error().
+				Interface("panic", err).
+				Str("method", c.Request.Method).
+				Str("path", c.Request.URL.Path).
+				Bytes("stack", debug.Stack()).
+				Msg("panic recovered")
+{{- else}}
+
+			logging.FromContext(c.Request.Context()).Error("panic recovered",
+				"panic", err,
+				"method", c.Request.Method,
+				"path", c.Request.URL.Path,
+				"stack", string(debug.Stack()),
+			)
+{{- end}}
+
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			AbortWithError(c, apperror.Internal(nil))
+		}()
+
+		c.Next()
+	}
+}
@@ -1,29 +1,43 @@
-package service
+// Package platform connects to the infrastructure the application runs
+// on. Its constructors return clients instead of setting globals; the
+// application container passes them to whatever needs them.
+package platform
 
 import (
+	"context"
 	"database/sql"
 	"os"
+	"time"
+
 	_ "github.com/lib/pq"
-)
+{{- if .UseTracing}}
 
-var DB *sql.DB
+	"github.com/XSAM/otelsql"
+	"go.opentelemetry.io/otel/attribute"
+{{- end}}
+)
 
-func InitPostgres() error {
-	var err error
-	DB, err = sql.Open("postgres", os.Getenv("DATABASE_URL"))
+// NewPostgres connects to the PostgreSQL database at DATABASE_URL.
+{{- if .UseTracing}}
+// Queries run with a context carrying a span, e.g. db.QueryContext(ctx,
+// ...), are traced.
+{{- end}}
+func NewPostgres(ctx context.Context) (*sql.DB, error) {
+{{- if .UseTracing}}
+	db, err := otelsql.Open("postgres", os.Getenv("DATABASE_URL"),
+		otelsql.WithAttributes(attribute.String("db.system", "postgresql")))
+{{- else}}
+	db, err := sql.Open("postgres", os.Getenv("DATABASE_URL"))
+{{- end}}
 	if err != nil {
-		return err
+		return nil, err
 	}
-	
-	if err := DB.Ping(); err != nil {
-		return err
-	}
-	
-	return nil
-}
 
-func ClosePostgres() {
-	if DB != nil {
-		DB.Close()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
 	}
-} 
\ No newline at end of file
+	return db, nil
+}
@@ -0,0 +1,10 @@
+# Prometheus configuration for docker compose. It scrapes the service
+# running on the host, e.g. with go run cmd/main.go.
+global:
+  scrape_interval: 15s
+
+scrape_configs:
+  - job_name: {{.ProjectName}}
+    metrics_path: /metrics
+    static_configs:
+      - targets: ["host.docker.internal:8080"]
@@ -0,0 +1,128 @@
+package app
+
+import (
+{{- $infra := or .UsePostgres .UseRedis .UseRabbitMQ}}
+{{- $auth := ne .Auth "none"}}
+{{- $std := or $infra $auth .UseMiddleware.ratelimit}}
+{{- if or $infra $auth}}
+	"context"
+{{- end}}
+{{- if .UsePostgres}}
+	"database/sql"
+{{- end}}
+{{- if $std}}
+	"fmt"
+{{- end}}
+{{- if $infra}}
+	"time"
+{{- end}}
+{{- if or $auth .UseRabbitMQ .UseRedis}}
+{{- if $std}}
+{{end}}
+{{- if $auth}}
+	"github.com/gin-gonic/gin"
+{{- end}}
+{{- if .UseRabbitMQ}}
+	amqp "github.com/rabbitmq/amqp091-go"
+{{- end}}
+{{- if .UseRedis}}
+	"github.com/redis/go-redis/v9"
+{{- end}}
+{{- end}}
+{{- if or $std $auth .UseRabbitMQ .UseRedis}}
+{{end}}
+	"{{.ModuleName}}/internal/handlers"
+{{- if $infra}}
+	"{{.ModuleName}}/internal/health"
+{{- end}}
+	"{{.ModuleName}}/internal/logging"
+{{- if or $auth .UseMiddleware.ratelimit}}
+	"{{.ModuleName}}/internal/middleware"
+{{- end}}
+{{- if $infra}}
+	"{{.ModuleName}}/internal/platform"
+{{- end}}
+	"{{.ModuleName}}/internal/routes"
+	"{{.ModuleName}}/internal/server"
+)
+{{- if .UsePostgres}}
+
+// newPostgres connects to PostgreSQL and registers its readiness check.
+func newPostgres(ctx context.Context, checks *health.Registry) (*sql.DB, error) {
+	db, err := platform.NewPostgres(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %v", err)
+	}
+	checks.Register("postgres", 2*time.Second, db.PingContext)
+	return db, nil
+}
+{{- end}}
+{{- if .UseRedis}}
+
+// newRedis connects to Redis and registers its readiness check.
+func newRedis(ctx context.Context, checks *health.Registry) (*redis.Client, error) {
+	client, err := platform.NewRedis(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+	checks.Register("redis", 2*time.Second, func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+	return client, nil
+}
+{{- end}}
+{{- if .UseRabbitMQ}}
+
+// newRabbitMQ connects to RabbitMQ and registers its readiness check.
+func newRabbitMQ(checks *health.Registry) (*amqp.Connection, error) {
+	conn, err := platform.NewRabbitMQ()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %v", err)
+	}
+	checks.Register("rabbitmq", 2*time.Second, func(ctx context.Context) error {
+		return platform.PingRabbitMQ(conn)
+	})
+	return conn, nil
+}
+{{- end}}
+
+{{- if ne .Auth "none"}}
+
+// newAuth returns the middleware authenticating the callers of the private
+// routes.
+func newAuth(ctx context.Context) (gin.HandlerFunc, error) {
+	auth, err := middleware.Auth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up authentication: %v", err)
+	}
+	return auth, nil
+}
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+
+// newLimiter returns the limiter of the rate limiting middleware.
+func newLimiter({{if .UseRedis}}client *redis.Client{{end}}) (middleware.Limiter, error) {
+	limiter, err := middleware.LimiterFromEnv({{if .UseRedis}}client{{end}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up rate limiting: %v", err)
+	}
+	return limiter, nil
+}
+{{- end}}
+
+// newServer returns the server of the routes of h{{if .OpenAPI}} and api{{end}}.
+func newServer(logger logging.Logger, h *handlers.Handlers{{if .OpenAPI}}, api *handlers.API{{end}}{{if .UseMiddleware.ratelimit}}, limiter middleware.Limiter{{end}}{{if ne .Auth "none"}}, auth gin.HandlerFunc{{end}}) (*server.Server, error) {
+	return server.NewServer(routes.Dependencies{
+		Logger:   logger,
+		Handlers: h,
+{{- if .OpenAPI}}
+		API:      api,
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+		Limiter:  limiter,
+{{- end}}
+{{- if ne .Auth "none"}}
+		Auth:     auth,
+{{- end}}
+	})
+}
@@ -1,24 +1,162 @@
-package service
+package platform
 
 import (
+{{- if .UseTracing}}
+	"context"
+{{- end}}
+	"errors"
+{{- if .UseTracing}}
+	"fmt"
+{{- end}}
 	"os"
+
 	amqp "github.com/rabbitmq/amqp091-go"
+{{- if .UseTracing}}
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+{{- if eq .Logger "zap"}}
+	"go.uber.org/zap"
+{{- end}}
+
+	"{{.ModuleName}}/internal/logging"
+{{- end}}
 )
 
-var RabbitMQ *amqp.Connection
+// NewRabbitMQ connects to the RabbitMQ broker at RABBITMQ_URL.
+func NewRabbitMQ() (*amqp.Connection, error) {
+	return amqp.Dial(os.Getenv("RABBITMQ_URL"))
+}
 
-func InitRabbitMQ() error {
-	var err error
-	RabbitMQ, err = amqp.Dial(os.Getenv("RABBITMQ_URL"))
+// PingRabbitMQ reports whether the broker of conn answers: opening a
+// channel needs an answer from it.
+func PingRabbitMQ(conn *amqp.Connection) error {
+	if conn.IsClosed() {
+		return errors.New("connection closed")
+	}
+	ch, err := conn.Channel()
 	if err != nil {
 		return err
 	}
-	
+	return ch.Close()
+}
+{{- if .UseTracing}}
+
+var tracer = otel.Tracer("{{.ModuleName}}/internal/platform")
+
+// Publish sends msg over conn to exchange with the routing key key in a
+// producer span. The trace context travels in the message headers, so
+// consumers continue the trace.
+func Publish(ctx context.Context, conn *amqp.Connection, exchange, key string, msg amqp.Publishing) error {
+	ctx, span := tracer.Start(ctx, "publish "+exchange,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination.name", exchange),
+			attribute.String("messaging.rabbitmq.destination.routing_key", key),
+		),
+	)
+	defer span.End()
+
+	if msg.Headers == nil {
+		msg.Headers = amqp.Table{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier(msg.Headers))
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return spanError(span, err)
+	}
+	defer ch.Close()
+
+	if err := ch.PublishWithContext(ctx, exchange, key, false, false, msg); err != nil {
+		return spanError(span, err)
+	}
 	return nil
 }
 
-func CloseRabbitMQ() {
-	if RabbitMQ != nil {
-		RabbitMQ.Close()
+// Consume passes every message of queue on conn to handle until ctx is
+// done or the channel closes. Each message is handled in a consumer span
+// that continues the trace of its publisher; it is acknowledged when
+// handle succeeds and requeued once otherwise. A message failing again is
+// rejected and logged: the broker drops it, or routes it to the
+// dead-letter exchange of queue if it has one, instead of redelivering it
+// forever.
+func Consume(ctx context.Context, conn *amqp.Connection, queue string, handle func(context.Context, amqp.Delivery) error) error {
+	ch, err := conn.Channel()
+	if err != nil {
+		return err
 	}
-} 
\ No newline at end of file
+	defer ch.Close()
+
+	deliveries, err := ch.Consume(queue, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case d, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("consuming %s: channel closed", queue)
+			}
+
+			msgCtx := otel.GetTextMapPropagator().Extract(ctx, headerCarrier(d.Headers))
+			msgCtx, span := tracer.Start(msgCtx, "process "+queue,
+				trace.WithSpanKind(trace.SpanKindConsumer),
+				trace.WithAttributes(
+					attribute.String("messaging.system", "rabbitmq"),
+					attribute.String("messaging.source.name", queue),
+				),
+			)
+			if err := handle(msgCtx, d); err != nil {
+				spanError(span, err)
+				if d.Redelivered {
+{{- if eq .Logger "zap"}}
+					logging.FromContext(msgCtx).Error("message rejected", zap.String("queue", queue), zap.String("message_id", d.MessageId), zap.Error(err))
+{{- else if eq .Logger "zerolog"}}
+					logging.FromContext(msgCtx).Error().Err(err).Str("queue", queue).Str("message_id", d.MessageId).Msg("message rejected")
+{{- else}}
+					logging.FromContext(msgCtx).Error("message rejected", "queue", queue, "message_id", d.MessageId, "error", err)
+{{- end}}
+				}
+				d.Nack(false, !d.Redelivered)
+			} else {
+				d.Ack(false)
+			}
+			span.End()
+		}
+	}
+}
+
+func spanError(span trace.Span, err error) error {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
+
+// headerCarrier lets OpenTelemetry propagators read and write AMQP
+// message headers.
+type headerCarrier amqp.Table
+
+func (c headerCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+{{- end}}
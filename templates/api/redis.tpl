@@ -1,22 +1,35 @@
-package service
+package platform
 
 import (
-	"github.com/redis/go-redis/v9"
+	"context"
 	"os"
+	"time"
+{{if .UseTracing}}
+	"github.com/redis/go-redis/extra/redisotel/v9"
+{{- end}}
+	"github.com/redis/go-redis/v9"
 )
 
-var RedisClient *redis.Client
-
-func InitRedis() error {
-	RedisClient = redis.NewClient(&redis.Options{
+// NewRedis connects to the Redis server at REDIS_URL.
+{{- if .UseTracing}}
+// Commands run with a context carrying a span are traced.
+{{- end}}
+func NewRedis(ctx context.Context) (*redis.Client, error) {
+	client := redis.NewClient(&redis.Options{
 		Addr: os.Getenv("REDIS_URL"),
 	})
-	
-	return nil
-}
+{{- if .UseTracing}}
+	if err := redisotel.InstrumentTracing(client); err != nil {
+		client.Close()
+		return nil, err
+	}
+{{- end}}
 
-func CloseRedis() {
-	if RedisClient != nil {
-		RedisClient.Close()
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, err
 	}
-} 
\ No newline at end of file
+	return client, nil
+}
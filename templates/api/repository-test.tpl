@@ -0,0 +1,56 @@
+//go:build integration
+
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"{{.ModuleName}}/internal/repository"
+	"{{.ModuleName}}/internal/testutil"
+)
+
+func TestWithTx(t *testing.T) {
+	db := testutil.Postgres(t)
+	testutil.Exec(t, db, "CREATE TABLE IF NOT EXISTS with_tx_test (id integer PRIMARY KEY)")
+	t.Cleanup(func() {
+		testutil.Exec(t, db, "DROP TABLE with_tx_test")
+	})
+	repos := repository.New(db)
+
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name string
+		err  error
+		rows int
+	}{
+		{name: "committed", err: nil, rows: 1},
+		{name: "rolled back", err: errFailed, rows: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testutil.Exec(t, db, "TRUNCATE with_tx_test")
+
+			err := repos.WithTx(context.Background(), func(tx *sql.Tx) error {
+				if _, err := tx.Exec("INSERT INTO with_tx_test (id) VALUES (1)"); err != nil {
+					return err
+				}
+				return tt.err
+			})
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Expected error %v, got %v", tt.err, err)
+			}
+
+			var rows int
+			if err := db.QueryRow("SELECT count(*) FROM with_tx_test").Scan(&rows); err != nil {
+				t.Fatalf("Failed to count rows: %v", err)
+			}
+			if rows != tt.rows {
+				t.Errorf("Expected %d rows, got %d", tt.rows, rows)
+			}
+		})
+	}
+}
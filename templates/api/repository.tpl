@@ -0,0 +1,47 @@
+// Package repository holds the data access of the application. Add a
+// repository per aggregate, such as a Users type with the queries of
+// users, and expose it from Repositories.
+package repository
+{{- if .UsePostgres}}
+
+import (
+	"context"
+	"database/sql"
+)
+{{- end}}
+
+// Repositories holds the repositories of the application.
+type Repositories struct {
+{{- if .UsePostgres}}
+	db *sql.DB
+{{- end}}
+}
+
+{{- if .UsePostgres}}
+
+// New returns the repositories, querying db.
+func New(db *sql.DB) *Repositories {
+	return &Repositories{db: db}
+}
+
+// WithTx runs fn in a transaction, committed when fn succeeds and rolled
+// back otherwise. Changes spanning several repositories go through the
+// *sql.Tx.
+func (r *Repositories) WithTx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+{{- else}}
+
+// New returns the repositories.
+func New() *Repositories {
+	return &Repositories{}
+}
+{{- end}}
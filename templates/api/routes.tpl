@@ -1,21 +1,140 @@
 package routes
 
 import (
+{{- if or .UseMiddleware.cors .UseMiddleware.bodylimit}}
+	"fmt"
+{{end}}
 	"github.com/gin-gonic/gin"
+
 	"{{.ModuleName}}/internal/handlers"
-	{{if .UseZap}}"{{.ModuleName}}/internal/middleware"{{end}}
+	"{{.ModuleName}}/internal/logging"
+{{- if .UseMetrics}}
+	"{{.ModuleName}}/internal/metrics"
+{{- end}}
+	"{{.ModuleName}}/internal/middleware"
+{{- if .OpenAPI}}
+	"{{.ModuleName}}/internal/openapi"
+{{- end}}
 )
 
-// SetupRoutes configures all the routes for the application
-func SetupRoutes(router *gin.Engine) {
-	{{if .UseZap}}// Add logging middleware
-	router.Use(middleware.LoggingMiddleware())
-	{{end}}
+// Dependencies holds what the routes and middleware need.
+type Dependencies struct {
+	Logger   logging.Logger
+	Handlers *handlers.Handlers
+{{- if .OpenAPI}}
+	// API implements the operations of the OpenAPI spec in api/
+	API *handlers.API
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+	// Limiter counts the requests of every client, as built by
+	// middleware.LimiterFromEnv
+	Limiter middleware.Limiter
+{{- end}}
+{{- if ne .Auth "none"}}
+	// Auth authenticates the callers of the private routes, as built by
+	// middleware.Auth
+	Auth gin.HandlerFunc
+{{- end}}
+}
+
+// SetupRoutes configures all the routes for the application. Middleware
+// runs in the order it is registered, and only for the routes registered
+// after it, so it all comes first.
+func SetupRoutes(router *gin.Engine, deps Dependencies) error {
+	h := deps.Handlers
+
+{{- if .UseMiddleware.requestid}}
+	// Give every request an ID, and a logger tagged with it, and log it
+	// once handled
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger(deps.Logger))
+{{- else}}
+	// Give every request an ID and a logger, and log it once handled
+	router.Use(middleware.RequestLogger(deps.Logger))
+{{- end}}
+
+	// Answer the errors handlers add with c.Error as problem+json
+	router.Use(middleware.Errors())
+{{- if .UseMiddleware.recovery}}
+
+	// Answer panics with 500 and log them with the request ID
+	router.Use(middleware.Recovery())
+{{- end}}
+{{- if .UseMetrics}}
+
+	// Record request metrics, including requests the middleware below
+	// rejects
+	router.Use(middleware.Metrics())
+{{- end}}
+{{- if .UseMiddleware.secure}}
+
+	// Security headers
+	router.Use(middleware.Secure())
+{{- end}}
+{{- if .UseMiddleware.cors}}
+
+	// Answer CORS preflights before requests can be rejected below;
+	// CORS_* in .env configure it
+	corsConfig, err := middleware.CORSConfigFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to set up CORS: %v", err)
+	}
+	router.Use(middleware.CORS(corsConfig))
+{{- end}}
+{{- if .UseMiddleware.bodylimit}}
+
+	// Reject bodies over MAX_BODY_BYTES
+	maxBodyBytes, err := middleware.MaxBodyBytesFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to set up the body size limit: %v", err)
+	}
+	router.Use(middleware.BodyLimit(maxBodyBytes))
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+
+	// Limit the requests of every client
+	router.Use(middleware.RateLimit(deps.Limiter))
+{{- end}}
+{{- if .UseMiddleware.gzip}}
+
+	// Compress responses
+	router.Use(middleware.Gzip())
+{{- end}}
+{{- if .UseMetrics}}
+
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+{{- end}}
+
+	// Unknown routes get a problem+json 404 too
+	router.NoRoute(h.NotFound)
+
+	// Liveness and readiness probes
+	router.GET("/livez", h.Livez)
+	router.GET("/readyz", h.Readyz)
 
 	// API routes
 	api := router.Group("/api")
 	{
-		api.GET("/ping", handlers.PingHandler)
-		api.GET("/health", handlers.HealthHandler)
+		api.GET("/ping", h.Ping)
 	}
-}
\ No newline at end of file
+{{- if .OpenAPI}}
+
+	// Operations of the OpenAPI spec in api/, validated against it, and
+	// the docs of the spec at /docs
+	openapi.RegisterRoutes(router, deps.API)
+	openapi.RegisterDocs(router)
+{{- end}}
+{{- if ne .Auth "none"}}
+
+	// Routes that need an authenticated caller. Restrict a route to some
+	// roles with middleware.RequireRole, e.g.
+	// private.DELETE("/users/:id", middleware.RequireRole("admin"), ...)
+	private := api.Group("", deps.Auth)
+	{
+		private.GET("/me", h.Me)
+	}
+{{- end}}
+
+	return nil
+}
@@ -1,32 +1,86 @@
 package server
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"net"
+	"net/http"
 	"os"
-	"{{.ModuleName}}/internal/routes"
+	"time"
+
 	"github.com/gin-gonic/gin"
+{{- if .UseTracing}}
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+{{- end}}
+
+	"{{.ModuleName}}/internal/routes"
+{{- if .UseTracing}}
+	"{{.ModuleName}}/internal/tracing"
+{{- end}}
 )
 
+// Server serves the routes of the application on PORT.
 type Server struct {
-	router *gin.Engine
+	http *http.Server
 }
 
-func NewServer() *Server {
-	return &Server{
-		router: gin.Default(),
-	}
-}
+// NewServer returns a server for the routes of deps. Requests are logged
+// by the request logging middleware instead of gin's own logger.
+func NewServer(deps routes.Dependencies) (*Server, error) {
+	router := gin.New()
+{{- if not .UseMiddleware.recovery}}
+	router.Use(gin.Recovery())
+{{- end}}
+{{- if .UseTracing}}
 
-func (s *Server) Start() error {
-	// Setup routes
-	routes.SetupRoutes(s.router)
+	// Trace every request; handlers continue the trace through
+	// c.Request.Context()
+	router.Use(otelgin.Middleware(tracing.ServiceName))
+{{- end}}
+
+	if err := routes.SetupRoutes(router, deps); err != nil {
+		return nil, err
+	}
 
-	// Get port from environment
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	// Start server
-	return s.router.Run(fmt.Sprintf(":%s", port))
-} 
\ No newline at end of file
+	return &Server{
+		http: &http.Server{
+			Addr:              ":" + port,
+			Handler:           router,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+	}, nil
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() string {
+	return s.http.Addr
+}
+
+// Start listens on Addr and serves requests until Shutdown.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ln)
+}
+
+// Serve serves the requests of ln until Shutdown, after which it returns
+// nil.
+func (s *Server) Serve(ln net.Listener) error {
+	if err := s.http.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops the server, letting the requests in flight finish until
+// ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.http.Shutdown(ctx)
+}
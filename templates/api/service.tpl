@@ -0,0 +1,50 @@
+// Package service holds the business logic of the application, between
+// the handlers and the repositories. Services get what they need through
+// New instead of reaching for package globals, so tests can build them
+// with fakes.
+package service
+
+import (
+	"context"
+{{- if .UseRabbitMQ}}
+
+	amqp "github.com/rabbitmq/amqp091-go"
+{{- end}}
+{{- if .UseRedis}}
+{{- if not .UseRabbitMQ}}
+{{end}}
+	"github.com/redis/go-redis/v9"
+{{- end}}
+
+	"{{.ModuleName}}/internal/repository"
+)
+
+// Services holds the services of the application and what they depend
+// on.
+type Services struct {
+	repos{{if .UseRabbitMQ}} {{end}} *repository.Repositories
+{{- if .UseRedis}}
+	cache{{if .UseRabbitMQ}} {{end}} *redis.Client
+{{- end}}
+{{- if .UseRabbitMQ}}
+	broker *amqp.Connection
+{{- end}}
+}
+
+// New returns the services of the application.
+func New(repos *repository.Repositories{{if .UseRedis}}, cache *redis.Client{{end}}{{if .UseRabbitMQ}}, broker *amqp.Connection{{end}}) *Services {
+	return &Services{
+		repos:{{if .UseRabbitMQ}} {{end}} repos,
+{{- if .UseRedis}}
+		cache:{{if .UseRabbitMQ}} {{end}} cache,
+{{- end}}
+{{- if .UseRabbitMQ}}
+		broker: broker,
+{{- end}}
+	}
+}
+
+// Ping returns the answer to a ping.
+func (s *Services) Ping(ctx context.Context) string {
+	return "pong"
+}
@@ -0,0 +1,143 @@
+// Package testutil helps the tests of the application: it builds its
+// handlers and router with dependencies fit for tests and sends requests
+// to them.
+{{- if .UsePostgres}}
+// Postgres, Exec and LoadFixture serve the integration tests, which run
+// against the database of docker-compose.
+{{- end}}
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+{{- if .UseMiddleware.ratelimit}}
+	"time"
+{{- end}}
+
+	"github.com/gin-gonic/gin"
+
+	"{{.ModuleName}}/internal/handlers"
+	"{{.ModuleName}}/internal/health"
+	"{{.ModuleName}}/internal/logging"
+{{- if or (ne .Auth "none") .UseMiddleware.ratelimit}}
+	"{{.ModuleName}}/internal/middleware"
+{{- end}}
+	"{{.ModuleName}}/internal/repository"
+	"{{.ModuleName}}/internal/routes"
+	"{{.ModuleName}}/internal/service"
+)
+
+// NewHandlers returns the handlers of the application on top of services
+// and repositories without connections, enough for the handlers that do
+// not reach them. Readyz runs the checks of checks.
+func NewHandlers(checks *health.Registry) *handlers.Handlers {
+	return handlers.New(newServices(), checks)
+}
+
+// newServices returns the services of the application on top of
+// repositories without connections.
+func newServices() *service.Services {
+	repos := repository.New({{if .UsePostgres}}nil{{end}})
+	return service.New(repos{{if .UseRedis}}, nil{{end}}{{if .UseRabbitMQ}}, nil{{end}})
+}
+
+// Dependencies returns the dependencies of the routes of h in tests. The
+// logger prints errors only.
+{{- if .OpenAPI}}
+// The API of the OpenAPI spec runs on services without connections.
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+// The limiter allows a million requests a minute.
+{{- end}}
+{{- if ne .Auth "none"}}
+// Authentication accepts every request as made by Caller("tester").
+{{- end}}
+// Change them before passing them to NewRouter.
+func Dependencies(t testing.TB, h *handlers.Handlers) routes.Dependencies {
+	t.Helper()
+
+	logger, err := logging.New("error", "text")
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return routes.Dependencies{
+		Logger:   logger,
+		Handlers: h,
+{{- if .OpenAPI}}
+		API:      handlers.NewAPI(newServices()),
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+		Limiter:  middleware.NewMemoryLimiter(1000000, time.Minute),
+{{- end}}
+{{- if ne .Auth "none"}}
+		Auth:     Authenticate(Caller("tester")),
+{{- end}}
+	}
+}
+
+// NewRouter returns the router of the application with the routes and
+// middleware SetupRoutes registers for deps.
+func NewRouter(t testing.TB, deps routes.Dependencies) *gin.Engine {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	if err := routes.SetupRoutes(router, deps); err != nil {
+		t.Fatalf("Failed to set up routes: %v", err)
+	}
+	return router
+}
+{{- if ne .Auth "none"}}
+
+// Caller returns the claims of a caller named subject with roles.
+func Caller(subject string, roles ...string) *middleware.Claims {
+	claims := &middleware.Claims{Roles: roles}
+	claims.Subject = subject
+	return claims
+}
+
+// Authenticate returns middleware accepting every request as made by the
+// caller of claims, in place of middleware.Auth.
+func Authenticate(claims *middleware.Claims) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(middleware.WithClaims(c.Request.Context(), claims))
+		c.Next()
+	}
+}
+{{- end}}
+
+// Do sends a request for target to handler, with body encoded as JSON
+// unless it is nil, and returns the recorded response.
+func Do(t testing.TB, handler http.Handler, method, target string, body interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("Failed to encode request body: %v", err)
+		}
+		r = bytes.NewReader(data)
+	}
+	req := httptest.NewRequest(method, target, r)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// DecodeJSON decodes the body of rec into v.
+func DecodeJSON(t testing.TB, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("Failed to decode response body %q: %v", rec.Body.String(), err)
+	}
+}
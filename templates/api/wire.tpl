@@ -0,0 +1,50 @@
+//go:build wireinject
+
+package app
+
+import (
+	"context"
+
+	"github.com/google/wire"
+
+	"{{.ModuleName}}/internal/handlers"
+	"{{.ModuleName}}/internal/health"
+	"{{.ModuleName}}/internal/logging"
+	"{{.ModuleName}}/internal/repository"
+	"{{.ModuleName}}/internal/service"
+)
+
+// New connects to the dependencies of the application and builds it.
+// Close the returned App once done.
+//
+// Wire generates its body in wire_gen.go from the providers passed to
+// wire.Build: run go generate ./internal/app after changing them.
+func New(ctx context.Context, logger logging.Logger) (*App, error) {
+	wire.Build(
+		health.NewRegistry,
+{{- if .UsePostgres}}
+		newPostgres,
+{{- end}}
+{{- if .UseRedis}}
+		newRedis,
+{{- end}}
+{{- if .UseRabbitMQ}}
+		newRabbitMQ,
+{{- end}}
+		repository.New,
+		service.New,
+		handlers.New,
+{{- if .OpenAPI}}
+		handlers.NewAPI,
+{{- end}}
+{{- if .UseMiddleware.ratelimit}}
+		newLimiter,
+{{- end}}
+{{- if ne .Auth "none"}}
+		newAuth,
+{{- end}}
+		newServer,
+		wire.Struct(new(App), "*"),
+	)
+	return nil, nil
+}
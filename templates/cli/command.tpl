@@ -33,4 +33,4 @@ func init() {
 	// Cobra supports local flags which will only run when this command
 	// is called directly, e.g.:
 	// {{.CommandName}}Cmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-} 
\ No newline at end of file
+}
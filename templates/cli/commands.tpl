@@ -32,4 +32,4 @@ func ConfirmAction(action string) bool {
 	fmt.Scanln(&response)
 	response = strings.ToLower(strings.TrimSpace(response))
 	return response == "y" || response == "yes"
-} 
\ No newline at end of file
+}
@@ -1,4 +1,4 @@
-package internal
+package config
 
 import (
 	"os"
@@ -11,10 +11,10 @@ import (
 // Config holds the application configuration
 type Config struct {
 	// General settings
-	AppName        string
-	Version        string
-	LogLevel       string
-	ConfigFile     string
+	AppName         string
+	Version         string
+	LogLevel        string
+	ConfigFile      string
 	LastUpdateCheck time.Time
 
 	// User settings
@@ -39,7 +39,7 @@ func NewConfig() *Config {
 // LoadConfig loads the configuration from disk
 func LoadConfig(configFile string) (*Config, error) {
 	config := NewConfig()
-	
+
 	if configFile != "" {
 		viper.SetConfigFile(configFile)
 	} else {
@@ -59,7 +59,7 @@ func LoadConfig(configFile string) (*Config, error) {
 	viper.SetDefault("AppName", config.AppName)
 	viper.SetDefault("Version", config.Version)
 	viper.SetDefault("LogLevel", config.LogLevel)
-	
+
 	// Try to read config file
 	if err := viper.ReadInConfig(); err != nil {
 		// It's okay if we don't find a config file
@@ -106,4 +106,4 @@ func (c *Config) SaveConfig() error {
 	viper.Set("TemplateDir", c.TemplateDir)
 
 	return viper.WriteConfig()
-} 
\ No newline at end of file
+}
@@ -1,8 +1,13 @@
 module {{.ModuleName}}
 
 go {{.GoVersion}}
+{{- if .GoToolchain}}
+
+toolchain {{.GoToolchain}}
+{{- end}}
 
 require (
-	github.com/spf13/cobra v1.8.0
-	github.com/spf13/viper v1.18.1
-) 
\ No newline at end of file
+{{- range .Requires}}
+	{{.Module}} {{.Version}}
+{{- end}}
+)
@@ -0,0 +1,50 @@
+// Package logging builds the application logger and carries it on the
+// command context.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+type contextKey struct{}
+
+// New returns a logger writing to stderr at level (debug, info, warn or
+// error) in format (text or json).
+func New(level, format string) (*zerolog.Logger, error) {
+	lvl, err := zerolog.ParseLevel(level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	var w io.Writer
+	switch format {
+	case "text":
+		w = zerolog.ConsoleWriter{Out: os.Stderr}
+	case "json":
+		w = os.Stderr
+	default:
+		return nil, fmt.Errorf("invalid log format %q: use text or json", format)
+	}
+
+	logger := zerolog.New(w).Level(lvl).With().Timestamp().Logger()
+	return &logger, nil
+}
+
+// WithContext returns a copy of ctx that carries logger.
+func WithContext(ctx context.Context, logger *zerolog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or a disabled logger.
+func FromContext(ctx context.Context) *zerolog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*zerolog.Logger); ok {
+		return logger
+	}
+	nop := zerolog.Nop()
+	return &nop
+}
@@ -6,4 +6,4 @@ import (
 
 func main() {
 	cmd.Execute()
-} 
\ No newline at end of file
+}
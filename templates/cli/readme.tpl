@@ -5,7 +5,7 @@
 ## Installation
 
 ```bash
-go get -u github.com/yourusername/{{.ProjectName}}
+go install {{.ModuleName}}@latest
 ```
 
 ## Usage
@@ -16,32 +16,31 @@ go get -u github.com/yourusername/{{.ProjectName}}
 
 ### Available Commands:
 
-* command1: Description of command1
-* command2: Description of command2
+* version: Print the version information
 * help: Help about any command
 
 ### Flags:
 
 * --config string: config file (default is $HOME/.{{.ProjectName}}.yaml)
 * -h, --help: help for {{.ProjectName}}
-* -t, --toggle: Help message for toggle
 
 Use "{{.ProjectName}} [command] --help" for more information about a command.
 
 ## Development
 
-1. Clone the repository
-2. Install dependencies with `go mod tidy`
-3. Run with `go run main.go`
+1. Install dependencies with `go mod tidy`
+2. Run with `go run . version`
+
+Commands live in `cmd/`, one file per command registered on `rootCmd`.
 
 ## Building
 
 Build a binary with:
 
 ```bash
-go build -o {{.ProjectName}}
+go build -o {{.ProjectName}} .
 ```
 
 ## License
 
-This project is licensed under the {{.License}} License - see the LICENSE file for details. 
\ No newline at end of file
+This project is licensed under the {{.License}} License - see the LICENSE file for details.
@@ -1,74 +1,74 @@
 package cmd
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
+
+	"{{.ModuleName}}/internal/config"
+{{- if ne .Logger "none"}}
+	"{{.ModuleName}}/internal/logging"
+{{- end}}
 )
 
-var cfgFile string
+var (
+{{- if eq .Logger "none"}}
+	cfgFile string
+{{- else}}
+	cfgFile   string
+	logLevel  string
+	logFormat string
+{{- end}}
+	// cfg is the configuration loaded before any command runs.
+	cfg *config.Config
+)
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "{{.ProjectName}}",
-	Short: "A brief description of your application",
-	Long: `A longer description that spans multiple lines and likely contains
-examples and usage of using your application. For example:
+	Use:          "{{.ProjectName}}",
+	Short:        "{{.ProjectDescription}}",
+	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		var err error
+		cfg, err = config.LoadConfig(cfgFile)
+{{- if eq .Logger "none"}}
+		return err
+{{- else}}
+		if err != nil {
+			return err
+		}
 
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	// Run: func(cmd *cobra.Command, args []string) { },
+		if logLevel == "" {
+			logLevel = cfg.LogLevel
+		}
+		logger, err := logging.New(logLevel, logFormat)
+		if err != nil {
+			return err
+		}
+		cmd.SetContext(logging.WithContext(cmd.Context(), logger))
+		return nil
+{{- end}}
+	},
+{{- if eq .Logger "zap"}}
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		_ = logging.FromContext(cmd.Context()).Sync()
+	},
+{{- end}}
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
 		os.Exit(1)
 	}
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
-
-	// Here you will define your flags and configuration settings.
-	// Cobra supports persistent flags, which, if defined here,
-	// will be global for your application.
+	// Persistent flags defined here are available to every command.
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.{{.ProjectName}}.yaml)")
-
-	// Cobra also supports local flags, which will only run
-	// when this action is called directly.
-	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+{{- if ne .Logger "none"}}
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "log level: debug, info, warn or error (default from config, info)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log format: text or json")
+{{- end}}
 }
-
-// initConfig reads in config file and ENV variables if set.
-func initConfig() {
-	if cfgFile != "" {
-		// Use config file from the flag.
-		viper.SetConfigFile(cfgFile)
-	} else {
-		// Find home directory.
-		home, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
-		}
-
-		// Search config in home directory with name ".{{.ProjectName}}" (without extension).
-		viper.AddConfigPath(home)
-		viper.SetConfigName(".{{.ProjectName}}")
-	}
-
-	viper.AutomaticEnv() // read in environment variables that match
-
-	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
-	}
-} 
\ No newline at end of file
@@ -1,8 +1,7 @@
-package internal
+package utils
 
 import (
 	"fmt"
-	"strings"
 )
 
 // Constants for terminal colors
@@ -44,4 +43,4 @@ func StringInSlice(a string, list []string) bool {
 		}
 	}
 	return false
-} 
\ No newline at end of file
+}
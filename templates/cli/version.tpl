@@ -4,6 +4,10 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+{{- if ne .Logger "none"}}
+
+	"{{.ModuleName}}/internal/logging"
+{{- end}}
 )
 
 var (
@@ -21,12 +25,19 @@ var versionCmd = &cobra.Command{
 	Short: "Print the version information",
 	Long:  `Print the version, build date, and git commit hash of the application.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Printf("{{.ProjectName}} version %s\n", Version)
-		fmt.Printf("Built on %s\n", BuildDate)
-		fmt.Printf("Git commit: %s\n", GitCommit)
+{{- if eq .Logger "zerolog"}}
+		logging.FromContext(cmd.Context()).Debug().Msg("printing version information")
+{{end}}
+{{- if or (eq .Logger "slog") (eq .Logger "zap")}}
+		logging.FromContext(cmd.Context()).Debug("printing version information")
+{{end}}
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "{{.ProjectName}} version %s\n", Version)
+		fmt.Fprintf(out, "Built on %s\n", BuildDate)
+		fmt.Fprintf(out, "Git commit: %s\n", GitCommit)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
-} 
\ No newline at end of file
+}
@@ -1,14 +1,19 @@
 package templates
 
 import (
+	"bytes"
 	"embed"
 	"fmt"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"text/template"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/go-sova/sova-cli/pkg/utils"
+	"github.com/go-sova/sova-cli/pkg/vfs"
 )
 
 //go:embed cli/* api/*
@@ -24,7 +29,7 @@ type TemplateLoader struct {
 func NewTemplateLoader() *TemplateLoader {
 	return &TemplateLoader{
 		fs:     TemplateFS,
-		logger: utils.NewLoggerWithPrefix(utils.Info, "TemplateLoader"),
+		logger: utils.NewComponentLogger("TemplateLoader"),
 	}
 }
 
@@ -32,6 +37,33 @@ func (l *TemplateLoader) SetLogger(logger *utils.Logger) {
 	l.logger = logger
 }
 
+// SetTemplateDir makes templates in dir take precedence over the embedded
+// templates with the same path, so dir/api/main.tpl replaces api/main.tpl.
+func (l *TemplateLoader) SetTemplateDir(dir string) {
+	l.fs = WithOverrides(dir)
+}
+
+// WithOverrides returns the embedded templates layered under the files in
+// dir. A missing dir leaves the embedded templates unchanged.
+func WithOverrides(dir string) fs.FS {
+	if dir == "" {
+		return TemplateFS
+	}
+	return &overrideFS{override: os.DirFS(dir), base: TemplateFS}
+}
+
+type overrideFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+func (o *overrideFS) Open(name string) (fs.File, error) {
+	if f, err := o.override.Open(name); err == nil {
+		return f, nil
+	}
+	return o.base.Open(name)
+}
+
 // LoadTemplate loads a template by name from the embedded filesystem
 func (l *TemplateLoader) LoadTemplate(name string) (*template.Template, error) {
 	// If the template name already includes a category prefix (e.g. "api/env.tpl"),
@@ -75,14 +107,17 @@ func (l *TemplateLoader) LoadTemplateFromCategory(category, name string) (*templ
 // FileGenerator handles generating files from templates
 type FileGenerator struct {
 	loader *TemplateLoader
+	fs     vfs.FS
 	logger *utils.Logger
 }
 
-// NewFileGenerator creates a new file generator
+// NewFileGenerator creates a new file generator that writes relative to the
+// current working directory
 func NewFileGenerator(loader *TemplateLoader) *FileGenerator {
 	return &FileGenerator{
 		loader: loader,
-		logger: utils.NewLoggerWithPrefix(utils.Info, "FileGenerator"),
+		fs:     vfs.NewOSFS("."),
+		logger: utils.NewComponentLogger("FileGenerator"),
 	}
 }
 
@@ -90,13 +125,19 @@ func (g *FileGenerator) SetLogger(logger *utils.Logger) {
 	g.logger = logger
 }
 
-// GenerateFile generates a file from a template
+// SetFS sets the filesystem generated files are written to
+func (g *FileGenerator) SetFS(fsys vfs.FS) {
+	g.fs = fsys
+}
+
+// GenerateFile renders a template to outputPath, a slash-separated path
+// relative to the generator's filesystem
 func (g *FileGenerator) GenerateFile(templateName, outputPath string, data interface{}) error {
 	g.logger.Debug("Generating file %s from template %s", outputPath, templateName)
 
 	// Create the directory if it doesn't exist
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
+	dir := path.Dir(outputPath)
+	if err := g.fs.MkdirAll(dir, 0755); err != nil {
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
@@ -106,21 +147,54 @@ func (g *FileGenerator) GenerateFile(templateName, outputPath string, data inter
 		return fmt.Errorf("failed to load template %s: %w", templateName, err)
 	}
 
-	// Create the output file
-	file, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create file %s: %w", outputPath, err)
-	}
-	defer file.Close()
-
 	// Execute the template
-	if err := tmpl.Execute(file, data); err != nil {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
 		return fmt.Errorf("failed to execute template %s: %w", templateName, err)
 	}
 
+	if err := g.fs.WriteFile(outputPath, buf.Bytes(), fileMode(outputPath)); err != nil {
+		return fmt.Errorf("failed to write file %s: %w", outputPath, err)
+	}
+
 	return nil
 }
 
+// fileMode returns the permissions of a generated file. Shell scripts are
+// made executable.
+func fileMode(outputPath string) fs.FileMode {
+	if path.Ext(outputPath) == ".sh" {
+		return 0755
+	}
+	return 0644
+}
+
+// InfoFile is the file, relative to a template directory, that holds the
+// template's metadata.
+const InfoFile = "template.yaml"
+
+// Info is the metadata a template declares in its template.yaml.
+type Info struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	// MinGoVersion is the oldest Go release the generated code builds with.
+	MinGoVersion string `yaml:"minGoVersion"`
+}
+
+// LoadInfo reads the metadata of the built-in template called name.
+func LoadInfo(name string) (*Info, error) {
+	data, err := fs.ReadFile(TemplateFS, path.Join(name, InfoFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata of template %s: %w", name, err)
+	}
+
+	var info Info
+	if err := yaml.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse metadata of template %s: %w", name, err)
+	}
+	return &info, nil
+}
+
 // GetTemplateFS returns the embedded filesystem containing all templates
 func GetTemplateFS() fs.FS {
 	return TemplateFS
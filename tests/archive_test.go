@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/archive"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/internal/project/api"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+)
+
+type archiveEntry struct {
+	name string
+	mode fs.FileMode
+}
+
+func readTarGz(t *testing.T, data []byte) []archiveEntry {
+	t.Helper()
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Failed to open gzip stream: %v", err)
+	}
+
+	var entries []archiveEntry
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read tar entry: %v", err)
+		}
+		if !header.ModTime.Equal(archive.ModTime) {
+			t.Errorf("%s: unexpected modification time %v", header.Name, header.ModTime)
+		}
+		entries = append(entries, archiveEntry{name: header.Name, mode: header.FileInfo().Mode()})
+	}
+	return entries
+}
+
+func readZip(t *testing.T, data []byte) []archiveEntry {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Failed to open zip archive: %v", err)
+	}
+
+	var entries []archiveEntry
+	for _, f := range zr.File {
+		if !f.Modified.Equal(archive.ModTime) {
+			t.Errorf("%s: unexpected modification time %v", f.Name, f.Modified)
+		}
+		entries = append(entries, archiveEntry{name: f.Name, mode: f.Mode()})
+	}
+	return entries
+}
+
+func generateArchive(t *testing.T, archivePath string) []byte {
+	t.Helper()
+
+	output, err := project.NewOutput(goldenName, goldenName, archivePath, "")
+	if err != nil {
+		t.Fatalf("Failed to prepare output: %v", err)
+	}
+
+	answers := &questions.ProjectAnswers{
+		ProjectName: goldenName,
+		ProjectType: "api",
+		Values:      questions.Answers{"UsePostgres": true},
+	}
+	generator := api.NewAPIProjectGenerator(goldenName, goldenName, answers)
+	generator.SetFS(output.FS())
+	generator.SetOutput(io.Discard)
+
+	if err := project.Run(generator); err != nil {
+		t.Fatalf("Failed to generate project: %v", err)
+	}
+	if err := output.Close(); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+
+	data, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+	return data
+}
+
+func TestProjectArchives(t *testing.T) {
+	testCases := []struct {
+		name string
+		file string
+		read func(*testing.T, []byte) []archiveEntry
+	}{
+		{name: "tar.gz", file: "project.tar.gz", read: readTarGz},
+		{name: "zip", file: "project.zip", read: readZip},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			first := generateArchive(t, filepath.Join(dir, "first-"+tc.file))
+			second := generateArchive(t, filepath.Join(dir, "second-"+tc.file))
+
+			if !bytes.Equal(first, second) {
+				t.Error("Archives generated from the same answers differ")
+			}
+
+			entries := tc.read(t, first)
+			names := make([]string, len(entries))
+			present := map[string]bool{}
+			for i, entry := range entries {
+				names[i] = entry.name
+				present[entry.name] = true
+				if !strings.HasPrefix(entry.name, goldenName+"/") {
+					t.Errorf("%s: entry is outside the top-level folder", entry.name)
+				}
+			}
+
+			if !sort.StringsAreSorted(names) {
+				t.Errorf("Entries are not sorted: %v", names)
+			}
+
+			for _, want := range []string{goldenName + "/", goldenName + "/internal/platform/postgres.go", goldenName + "/go.mod"} {
+				if !present[want] {
+					t.Errorf("Expected entry %s in %v", want, names)
+				}
+			}
+		})
+	}
+}
+
+func TestArchivePreservesModes(t *testing.T) {
+	fsys := vfs.NewMemFS()
+	if err := fsys.MkdirAll("scripts", 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := fsys.WriteFile("scripts/build.sh", []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := fsys.WriteFile("README.md", []byte("# demo\n"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	for _, tc := range []struct {
+		format archive.Format
+		read   func(*testing.T, []byte) []archiveEntry
+	}{
+		{format: archive.TarGz, read: readTarGz},
+		{format: archive.Zip, read: readZip},
+	} {
+		var buf bytes.Buffer
+		if err := archive.Write(&buf, tc.format, fsys, "demo"); err != nil {
+			t.Fatalf("%s: failed to write archive: %v", tc.format, err)
+		}
+
+		modes := map[string]fs.FileMode{}
+		for _, entry := range tc.read(t, buf.Bytes()) {
+			modes[entry.name] = entry.mode
+		}
+
+		if modes["demo/scripts/build.sh"].Perm() != 0755 {
+			t.Errorf("%s: script mode = %v, want 0755", tc.format, modes["demo/scripts/build.sh"])
+		}
+		if modes["demo/README.md"].Perm() != 0644 {
+			t.Errorf("%s: README mode = %v, want 0644", tc.format, modes["demo/README.md"])
+		}
+		if !modes["demo/scripts/"].IsDir() {
+			t.Errorf("%s: scripts/ is not recorded as a directory", tc.format)
+		}
+	}
+}
@@ -2,12 +2,53 @@ package tests
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 )
 
+// sovaBinary is the sova executable built once for the whole package.
+var sovaBinary string
+
+func TestMain(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	buildDir, err := os.MkdirTemp("", "sova-bin-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create build directory: %v\n", err)
+		return 1
+	}
+	defer os.RemoveAll(buildDir)
+
+	sovaBinary = filepath.Join(buildDir, "sova")
+	build := exec.Command("go", "build", "-o", sovaBinary, "..")
+	if output, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to build sova: %v\n%s", err, output)
+		return 1
+	}
+
+	return m.Run()
+}
+
+func runSova(t *testing.T, dir string, args ...string) (string, error) {
+	t.Helper()
+
+	cmd := exec.Command(sovaBinary, args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.String() + stderr.String(), err
+}
+
 func TestCLICommands(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "sova-cli-test-*")
 	if err != nil {
@@ -15,6 +56,11 @@ func TestCLICommands(t *testing.T) {
 	}
 	defer os.RemoveAll(tempDir)
 
+	configFile, err := filepath.Abs("testdata/sova.yaml")
+	if err != nil {
+		t.Fatalf("Failed to resolve config file: %v", err)
+	}
+
 	testCases := []struct {
 		name          string
 		args          []string
@@ -24,7 +70,7 @@ func TestCLICommands(t *testing.T) {
 		{
 			name:          "Version command",
 			args:          []string{"version"},
-			expectedOut:   "Sova CLI version",
+			expectedOut:   "Sova CLI v",
 			expectedError: false,
 		},
 		{
@@ -34,30 +80,34 @@ func TestCLICommands(t *testing.T) {
 			expectedError: false,
 		},
 		{
-			name:          "Init command with project name",
-			args:          []string{"init", "test-project"},
-			expectedOut:   "Project initialized successfully",
+			name:          "Init help lists project types",
+			args:          []string{"init", "--help"},
+			expectedOut:   "api: A Go API project",
+			expectedError: false,
+		},
+		{
+			name:          "Config get reads the config file",
+			args:          []string{"config", "get", "defaults.goVersion", "--config", configFile},
+			expectedOut:   "1.22",
 			expectedError: false,
 		},
+		{
+			name:          "Config get rejects unknown keys",
+			args:          []string{"config", "get", "defaults.colour"},
+			expectedOut:   "unknown configuration key",
+			expectedError: true,
+		},
 		{
 			name:          "Invalid command",
 			args:          []string{"invalid-command"},
-			expectedOut:   "",
+			expectedOut:   "unknown command",
 			expectedError: true,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cmd := exec.Command("go", append([]string{"run", "../main.go"}, tc.args...)...)
-			cmd.Dir = tempDir
-
-			var stdout, stderr bytes.Buffer
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-
-			err := cmd.Run()
-			output := stdout.String() + stderr.String()
+			output, err := runSova(t, tempDir, tc.args...)
 
 			if tc.expectedError && err == nil {
 				t.Errorf("Expected error but got none")
@@ -87,17 +137,17 @@ func TestCLIFlags(t *testing.T) {
 		expectedError bool
 	}{
 		{
-			name:          "Init with template flag",
-			args:          []string{"init", "test-project"},
-			flags:         []string{"--template", "default"},
-			expectedOut:   "Project initialized successfully",
+			name:          "Version with verbose flag",
+			args:          []string{"version"},
+			flags:         []string{"--verbose"},
+			expectedOut:   "Go Version:",
 			expectedError: false,
 		},
 		{
-			name:          "Init with invalid template",
+			name:          "Init with unknown flag",
 			args:          []string{"init", "test-project"},
-			flags:         []string{"--template", "nonexistent"},
-			expectedOut:   "",
+			flags:         []string{"--nonexistent"},
+			expectedOut:   "unknown flag",
 			expectedError: true,
 		},
 		{
@@ -111,17 +161,7 @@ func TestCLIFlags(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cmdArgs := append([]string{"run", "../main.go"}, tc.args...)
-			cmdArgs = append(cmdArgs, tc.flags...)
-			cmd := exec.Command("go", cmdArgs...)
-			cmd.Dir = tempDir
-
-			var stdout, stderr bytes.Buffer
-			cmd.Stdout = &stdout
-			cmd.Stderr = &stderr
-
-			err := cmd.Run()
-			output := stdout.String() + stderr.String()
+			output, err := runSova(t, tempDir, append(tc.args, tc.flags...)...)
 
 			if tc.expectedError && err == nil {
 				t.Errorf("Expected error but got none")
@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/templates"
+	"github.com/spf13/viper"
+)
+
+func loadConfig(t *testing.T, contents string) *config.Config {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".sova.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+
+	cfg, err := config.Load(v)
+	if err != nil {
+		t.Fatalf("Failed to load config: %v", err)
+	}
+	return cfg
+}
+
+func TestConfigResolution(t *testing.T) {
+	contents := `
+defaults:
+  goVersion: "1.22"
+  author: Jane Doe
+  license: Apache-2.0
+templates:
+  default: cli
+project:
+  structure:
+    enableDocs: false
+`
+
+	t.Run("defaults", func(t *testing.T) {
+		cfg, err := config.Load(viper.New())
+		if err != nil {
+			t.Fatalf("Failed to load config: %v", err)
+		}
+		want := config.Default()
+		if cfg.Defaults != want.Defaults || cfg.Project != want.Project {
+			t.Errorf("Expected %+v, got %+v", want, cfg)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		cfg := loadConfig(t, contents)
+		if cfg.Defaults.GoVersion != "1.22" || cfg.Defaults.Author != "Jane Doe" || cfg.Defaults.License != "Apache-2.0" {
+			t.Errorf("File defaults were not applied: %+v", cfg.Defaults)
+		}
+		if cfg.Defaults.Template != "cli" {
+			t.Errorf("Expected templates.default to select cli, got %s", cfg.Defaults.Template)
+		}
+		if cfg.Project.Structure.EnableDocs || !cfg.Project.Structure.EnableTests {
+			t.Errorf("Structure settings were not applied: %+v", cfg.Project.Structure)
+		}
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv("SOVA_DEFAULT_AUTHOR", "Env Author")
+		t.Setenv("SOVA_ENABLE_DOCS", "true")
+		t.Setenv("SOVA_DEFAULT_TEMPLATE", "api")
+
+		cfg := loadConfig(t, contents)
+		if cfg.Defaults.Author != "Env Author" {
+			t.Errorf("Expected SOVA_DEFAULT_AUTHOR to win, got %s", cfg.Defaults.Author)
+		}
+		if !cfg.Project.Structure.EnableDocs {
+			t.Error("Expected SOVA_ENABLE_DOCS to win")
+		}
+		if cfg.Defaults.Template != "api" {
+			t.Errorf("Expected defaults.template to win over templates.default, got %s", cfg.Defaults.Template)
+		}
+		if cfg.Defaults.GoVersion != "1.22" {
+			t.Errorf("Expected file value for unset variables, got %s", cfg.Defaults.GoVersion)
+		}
+	})
+
+	t.Run("invalid go version", func(t *testing.T) {
+		v := viper.New()
+		v.Set("defaults.goVersion", "latest")
+		cfg, err := config.Load(v)
+		if err == nil {
+			t.Error("Expected an error for an invalid go version")
+		}
+		if cfg.Defaults.GoVersion != config.Default().Defaults.GoVersion {
+			t.Errorf("Expected fallback go version, got %s", cfg.Defaults.GoVersion)
+		}
+	})
+}
+
+func TestConfigSetInFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".sova.yaml")
+	original := "# my settings\ndefaults:\n  license: MIT # keep\ntools:\n  enableLinting: true\n"
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	for _, set := range [][2]string{
+		{"defaults.goVersion", "1.23"},
+		{"defaults.license", "BSD-3-Clause"},
+		{"project.structure.enableScripts", "false"},
+	} {
+		if err := config.SetInFile(path, set[0], set[1]); err != nil {
+			t.Fatalf("Failed to set %s: %v", set[0], err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	for _, want := range []string{"# my settings", "license: BSD-3-Clause # keep", `goVersion: "1.23"`, "enableLinting: true", "enableScripts: false"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %q in config file:\n%s", want, data)
+		}
+	}
+
+	cfg := loadConfig(t, string(data))
+	if cfg.Defaults.GoVersion != "1.23" || cfg.Project.Structure.EnableScripts {
+		t.Errorf("Written settings did not load back: %+v", cfg)
+	}
+
+	for _, bad := range [][2]string{
+		{"defaults.goVersion", "latest"},
+		{"project.structure.enableDocs", "maybe"},
+		{"unknown.key", "x"},
+	} {
+		if err := config.SetInFile(path, bad[0], bad[1]); err == nil {
+			t.Errorf("Expected an error setting %s to %s", bad[0], bad[1])
+		}
+	}
+}
+
+func TestGeneratorsUseConfig(t *testing.T) {
+	cfg := testConfig()
+	cfg.Defaults.GoVersion = "1.23.4"
+	cfg.Project.Structure = config.Structure{EnableTests: true}
+
+	for _, projectType := range project.ProjectTypes() {
+		t.Run(projectType, func(t *testing.T) {
+			answers, err := questions.ResolveAnswers(templates.TemplateFS, projectType, nil)
+			if err != nil {
+				t.Fatalf("Failed to resolve answers: %v", err)
+			}
+
+			gen, err := project.NewGenerator(projectType, goldenName, goldenName, answers)
+			if err != nil {
+				t.Fatalf("Failed to create generator: %v", err)
+			}
+			gen.SetConfig(cfg)
+
+			files, dirs, err := gen.Generate()
+			if err != nil {
+				t.Fatalf("Failed to plan project: %v", err)
+			}
+			present := map[string]bool{}
+			for _, dir := range dirs {
+				present[dir] = true
+			}
+			if !present["test"] || present["docs"] || present["scripts"] {
+				t.Errorf("Structure settings were not applied: %v", dirs)
+			}
+
+			got, err := renderProject(gen)
+			if err != nil {
+				t.Fatalf("Failed to render project: %v", err)
+			}
+			want := map[string]string{
+				"go.mod":                   "go 1.23.0\n\ntoolchain go1.23.4\n",
+				"Dockerfile":               "FROM golang:1.23-alpine",
+				".github/workflows/ci.yml": `go-version: "1.23"`,
+			}
+			for path, text := range want {
+				if _, ok := files[path]; !ok {
+					continue
+				}
+				if !strings.Contains(string(got[path]), text) {
+					t.Errorf("%s does not use the configured go version:\n%s", path, got[path])
+				}
+			}
+		})
+	}
+}
+
+func TestTemplateDirectoryOverrides(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "api"), 0755); err != nil {
+		t.Fatalf("Failed to create template directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "api", "gitignore.tpl"), []byte("custom {{.ProjectName}}\n"), 0644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	cfg := testConfig()
+	cfg.Templates.Directory = dir
+
+	answers, err := questions.ResolveAnswers(templates.TemplateFS, "api", nil)
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	gen, err := project.NewGenerator("api", goldenName, goldenName, answers)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	gen.SetConfig(cfg)
+
+	got, err := renderProject(gen)
+	if err != nil {
+		t.Fatalf("Failed to render project: %v", err)
+	}
+	if string(got[".gitignore"]) != "custom demo\n" {
+		t.Errorf("Expected the override template to be used, got %q", got[".gitignore"])
+	}
+	if _, err := fs.Stat(os.DirFS(dir), "api/main.tpl"); err == nil || len(got["cmd/main.go"]) == 0 {
+		t.Error("Expected built-in templates for files without an override")
+	}
+}
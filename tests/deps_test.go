@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"unicode"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/deps"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+func TestDependencyConstraints(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		allowed    bool
+	}{
+		{"", "v1.2.3", true},
+		{">= v1.9.0, < v2.0.0", "v1.9.0", true},
+		{">= v1.9.0, < v2.0.0", "v1.10.2", true},
+		{">= v1.9.0, < v2.0.0", "v1.8.9", false},
+		{">= v1.9.0, < v2.0.0", "v2.0.0", false},
+		{">= v1.9.0, < v2.0.0", "v2.0.0-rc.1", true},
+		{"!= v1.5.0", "v1.5.0", false},
+		{"v1.5.0", "v1.5.0", true},
+		{"", "1.2.3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			c, err := deps.ParseConstraint(tt.constraint)
+			if err != nil {
+				t.Fatalf("Failed to parse constraint: %v", err)
+			}
+			if got := c.Allows(tt.version); got != tt.allowed {
+				t.Errorf("Allows(%s) = %v, want %v", tt.version, got, tt.allowed)
+			}
+		})
+	}
+
+	for _, bad := range []string{"~> v1.0.0", ">= 1.0", "<"} {
+		if _, err := deps.ParseConstraint(bad); err == nil {
+			t.Errorf("Expected an error parsing %q", bad)
+		}
+	}
+}
+
+func TestDependencyCatalog(t *testing.T) {
+	catalog, err := deps.ParseCatalog([]byte(`
+dependencies:
+  - module: example.com/b
+    version: v1.2.0
+    constraint: "< v2.0.0"
+  - module: example.com/a
+    version: v0.3.0
+    when: .UseA
+`))
+	if err != nil {
+		t.Fatalf("Failed to parse catalog: %v", err)
+	}
+
+	requires, err := catalog.Requirements(map[string]interface{}{"UseA": true}, map[string]string{"example.com/b": "v1.4.0"})
+	if err != nil {
+		t.Fatalf("Failed to resolve requirements: %v", err)
+	}
+	if len(requires) != 2 || requires[0].Module != "example.com/a" || requires[1].Version != "v1.4.0" || !requires[1].Overridden {
+		t.Errorf("Unexpected requirements: %+v", requires)
+	}
+
+	requires, err = catalog.Requirements(nil, nil)
+	if err != nil || len(requires) != 1 {
+		t.Errorf("Expected only the unconditional module, got %+v (%v)", requires, err)
+	}
+
+	if _, err := catalog.Requirements(nil, map[string]string{"example.com/b": "v2.0.0"}); err == nil {
+		t.Error("Expected an error for an override outside the constraint")
+	}
+
+	invalid := []string{
+		"dependencies:\n  - version: v1.0.0\n",
+		"dependencies:\n  - module: a\n    version: 1.0\n",
+		"dependencies:\n  - module: a\n    version: v3.0.0\n    constraint: \"< v2.0.0\"\n",
+		"dependencies:\n  - module: a\n    version: v1.0.0\n  - module: a\n    version: v1.0.0\n",
+	}
+	for _, data := range invalid {
+		if _, err := deps.ParseCatalog([]byte(data)); err == nil {
+			t.Errorf("Expected an error parsing:\n%s", data)
+		}
+	}
+}
+
+func TestBuiltinDependencyCatalogs(t *testing.T) {
+	for _, projectType := range project.ProjectTypes() {
+		t.Run(projectType, func(t *testing.T) {
+			catalog, err := project.DependencyCatalog(projectType, testConfig())
+			if err != nil {
+				t.Fatalf("Failed to load catalog: %v", err)
+			}
+			if len(catalog.Dependencies) == 0 {
+				t.Error("Expected the template to declare its dependencies")
+			}
+
+			// The go-redis extras are released together with the client.
+			versions := make(map[string]string, len(catalog.Dependencies))
+			for _, d := range catalog.Dependencies {
+				versions[d.Module] = d.Version
+			}
+			if v, ok := versions["github.com/redis/go-redis/extra/redisotel/v9"]; ok && v != versions["github.com/redis/go-redis/v9"] {
+				t.Errorf("redisotel %s is not in step with go-redis %s", v, versions["github.com/redis/go-redis/v9"])
+			}
+		})
+	}
+}
+
+// TestDependencyVersionsResolve asks the module proxy for every version the
+// built-in catalogs pin, so a version that does not exist fails here rather
+// than in the go mod tidy of a generated project. It reaches the network,
+// so it only runs with SOVA_NETWORK_TESTS set.
+func TestDependencyVersionsResolve(t *testing.T) {
+	if os.Getenv("SOVA_NETWORK_TESTS") == "" || testing.Short() {
+		t.Skip("skipping module proxy lookups: set SOVA_NETWORK_TESTS=1 to run them")
+	}
+	proxy := moduleProxy()
+	if proxy == "" {
+		t.Skip("no module proxy configured in GOPROXY")
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, projectType := range project.ProjectTypes() {
+		catalog, err := project.DependencyCatalog(projectType, testConfig())
+		if err != nil {
+			t.Fatalf("Failed to load %s catalog: %v", projectType, err)
+		}
+		for _, d := range catalog.Dependencies {
+			url := fmt.Sprintf("%s/%s/@v/%s.info", proxy, escapeModulePath(d.Module), d.Version)
+			resp, err := client.Get(url)
+			if err != nil {
+				t.Skipf("module proxy unreachable: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("%s: %s %s does not resolve: %s", projectType, d.Module, d.Version, resp.Status)
+			}
+		}
+	}
+}
+
+// moduleProxy returns the first proxy URL of GOPROXY, or the default proxy
+// when it is unset.
+func moduleProxy() string {
+	env := os.Getenv("GOPROXY")
+	if env == "" {
+		return "https://proxy.golang.org"
+	}
+	for _, entry := range strings.FieldsFunc(env, func(r rune) bool { return r == ',' || r == '|' }) {
+		if strings.HasPrefix(entry, "https://") || strings.HasPrefix(entry, "http://") {
+			return strings.TrimSuffix(entry, "/")
+		}
+	}
+	return ""
+}
+
+// escapeModulePath applies the case encoding of the module proxy protocol.
+func escapeModulePath(module string) string {
+	var b strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			b.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func TestDependencyOverrides(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sova.yaml")
+	if err := config.SetDependency(path, "github.com/gin-gonic/gin", "v1.10.0"); err != nil {
+		t.Fatalf("Failed to set dependency: %v", err)
+	}
+
+	versions, err := config.LoadDependencies(path)
+	if err != nil {
+		t.Fatalf("Failed to load dependencies: %v", err)
+	}
+	if versions["github.com/gin-gonic/gin"] != "v1.10.0" {
+		t.Fatalf("Unexpected dependencies: %v", versions)
+	}
+
+	cfg := testConfig()
+	cfg.Dependencies = versions
+	answers, err := questions.ResolveAnswers(templates.TemplateFS, "api", nil)
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	gen := newGenerator(t, renderCase{projectType: "api", answers: answers})
+	gen.SetConfig(cfg)
+	files, err := renderProject(gen)
+	if err != nil {
+		t.Fatalf("Failed to render project: %v", err)
+	}
+	if !strings.Contains(string(files["go.mod"]), "github.com/gin-gonic/gin v1.10.0\n") {
+		t.Errorf("go.mod does not use the configured version:\n%s", files["go.mod"])
+	}
+
+	if err := config.UnsetDependency(path, "github.com/gin-gonic/gin"); err != nil {
+		t.Fatalf("Failed to unset dependency: %v", err)
+	}
+	if versions, _ := config.LoadDependencies(path); len(versions) != 0 {
+		t.Errorf("Expected no dependencies after unset, got %v", versions)
+	}
+}
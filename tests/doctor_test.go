@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/doctor"
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/internal/project/api"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+)
+
+// generateDoctorProject writes an API project with PostgreSQL and Redis
+// to a temporary directory.
+func generateDoctorProject(t *testing.T) string {
+	t.Helper()
+
+	c, err := newRenderCase("api", "doctor", map[string]interface{}{"UsePostgres": true, "UseRedis": true})
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), goldenName)
+	gen := newGenerator(t, c)
+	gen.SetFS(vfs.NewOSFS(dir))
+	gen.SetEvents(events.Discard)
+	if err := project.Run(gen); err != nil {
+		t.Fatalf("Failed to generate project: %v", err)
+	}
+	return dir
+}
+
+func editFile(t *testing.T, path string, edit func(string) string) {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(edit(string(data))), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	testCases := []struct {
+		name     string
+		breakIt  func(t *testing.T, dir string)
+		status   doctor.Status
+		message  string
+		fix      string
+		failures int
+	}{
+		{
+			name:    "Healthy project",
+			breakIt: func(t *testing.T, dir string) {},
+		},
+		{
+			name: "Missing file",
+			breakIt: func(t *testing.T, dir string) {
+				os.Remove(filepath.Join(dir, "internal/platform/redis.go"))
+			},
+			status:   doctor.Fail,
+			message:  "internal/platform/redis.go is missing",
+			fix:      "restore it",
+			failures: 1,
+		},
+		{
+			name: "Empty directories left out of version control",
+			breakIt: func(t *testing.T, dir string) {
+				for _, d := range []string{"docs", "scripts", "test"} {
+					if err := os.Remove(filepath.Join(dir, d)); err != nil {
+						t.Fatalf("Failed to remove %s: %v", d, err)
+					}
+				}
+			},
+		},
+		{
+			name: "Missing module",
+			breakIt: func(t *testing.T, dir string) {
+				editFile(t, filepath.Join(dir, "go.mod"), func(s string) string {
+					return strings.Replace(s, "github.com/lib/pq", "// github.com/lib/pq", 1)
+				})
+			},
+			status:   doctor.Fail,
+			message:  "go.mod does not require github.com/lib/pq",
+			fix:      "go get github.com/lib/pq@",
+			failures: 1,
+		},
+		{
+			name: "Old go directive",
+			breakIt: func(t *testing.T, dir string) {
+				editFile(t, filepath.Join(dir, "go.mod"), func(s string) string {
+					return strings.Replace(s, "go 1.21.0", "go 1.20", 1)
+				})
+			},
+			status:   doctor.Fail,
+			message:  "go.mod declares go 1.20",
+			fix:      "go mod edit -go=1.21.0",
+			failures: 1,
+		},
+		{
+			name: "Missing variable",
+			breakIt: func(t *testing.T, dir string) {
+				editFile(t, filepath.Join(dir, ".env"), func(s string) string {
+					return strings.Replace(s, "REDIS_URL=", "# REDIS_URL=", 1)
+				})
+			},
+			status:   doctor.Fail,
+			message:  ".env does not set REDIS_URL",
+			fix:      "add REDIS_URL=localhost:6379 to .env",
+			failures: 1,
+		},
+		{
+			name: "Missing service",
+			breakIt: func(t *testing.T, dir string) {
+				editFile(t, filepath.Join(dir, "docker-compose.yml"), func(s string) string {
+					return strings.Replace(s, "  redis:", "  cache:", 1)
+				})
+			},
+			status:   doctor.Fail,
+			message:  "docker-compose.yml has no redis service",
+			fix:      "image redis:latest",
+			failures: 1,
+		},
+		{
+			name: "Extra service",
+			breakIt: func(t *testing.T, dir string) {
+				editFile(t, filepath.Join(dir, "docker-compose.yml"), func(s string) string {
+					return strings.Replace(s, "services:\n", "services:\n  mailhog:\n    image: mailhog/mailhog\n", 1)
+				})
+			},
+			status:  doctor.Warn,
+			message: "docker-compose.yml runs mailhog",
+			fix:     "remove the mailhog service",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := generateDoctorProject(t)
+			tc.breakIt(t, dir)
+
+			report, err := doctor.Run(vfs.NewOSFS(dir), testConfig())
+			if err != nil {
+				t.Fatalf("Failed to run doctor: %v", err)
+			}
+
+			if got := report.Failures(); got != tc.failures {
+				t.Errorf("Expected %d failures, got %d: %+v", tc.failures, got, report.Results)
+			}
+			if tc.message == "" {
+				return
+			}
+
+			for _, result := range report.Results {
+				if strings.Contains(result.Message, tc.message) {
+					if result.Status != tc.status {
+						t.Errorf("Expected status %d for %q, got %d", tc.status, result.Message, result.Status)
+					}
+					if !strings.Contains(result.Fix, tc.fix) {
+						t.Errorf("Expected fix containing %q, got %q", tc.fix, result.Fix)
+					}
+					return
+				}
+			}
+			t.Errorf("Expected a result containing %q, got %+v", tc.message, report.Results)
+		})
+	}
+}
+
+func TestDoctorNeedsManifest(t *testing.T) {
+	_, err := doctor.Run(vfs.NewOSFS(t.TempDir()), testConfig())
+	if err == nil || !strings.Contains(err.Error(), project.ManifestFile) {
+		t.Errorf("Expected an error naming %s, got %v", project.ManifestFile, err)
+	}
+}
+
+func TestDoctorOpenAPI(t *testing.T) {
+	c, err := newRenderCase("api", "openapi", nil)
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name    string
+		remove  string
+		message string
+	}{
+		{name: "Healthy project"},
+		{name: "Missing generated file", remove: "internal/openapi/server.go", message: "internal/openapi/server.go is missing"},
+		{name: "Missing operation", remove: "internal/handlers/get_pet.go", message: "internal/handlers does not implement every operation of api/openapi.yaml"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), goldenName)
+			gen := api.NewAPIProjectGenerator(goldenName, goldenName, c.answers)
+			gen.SetConfig(testConfig())
+			gen.SetOpenAPI(loadPetstore(t))
+			gen.SetFS(vfs.NewOSFS(dir))
+			gen.SetEvents(events.Discard)
+			if err := project.Run(gen); err != nil {
+				t.Fatalf("Failed to generate project: %v", err)
+			}
+			if tc.remove != "" {
+				if err := os.Remove(filepath.Join(dir, filepath.FromSlash(tc.remove))); err != nil {
+					t.Fatalf("Failed to remove %s: %v", tc.remove, err)
+				}
+			}
+
+			report, err := doctor.Run(vfs.NewOSFS(dir), testConfig())
+			if err != nil {
+				t.Fatalf("Failed to run doctor: %v", err)
+			}
+			if tc.message == "" {
+				if report.Failures() != 0 {
+					t.Errorf("Expected no failures, got %+v", report.Results)
+				}
+				return
+			}
+			if report.Failures() != 1 {
+				t.Errorf("Expected 1 failure, got %+v", report.Results)
+			}
+			for _, result := range report.Results {
+				if result.Status == doctor.Fail && strings.Contains(result.Message, tc.message) {
+					return
+				}
+			}
+			t.Errorf("Expected a failure containing %q, got %+v", tc.message, report.Results)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+)
+
+// recorder is a sink that keeps every event it receives.
+type recorder struct {
+	mu     sync.Mutex
+	events []events.Event
+}
+
+func (r *recorder) Emit(e events.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, e)
+}
+
+func TestGeneratorEvents(t *testing.T) {
+	for _, projectType := range project.ProjectTypes() {
+		t.Run(projectType, func(t *testing.T) {
+			c, err := newRenderCase(projectType, "default", nil)
+			if err != nil {
+				t.Fatalf("Failed to resolve answers: %v", err)
+			}
+			gen := newGenerator(t, c)
+			gen.SetFS(vfs.NewMemFS())
+			rec := &recorder{}
+			gen.SetEvents(rec)
+
+			if err := project.Run(gen); err != nil {
+				t.Fatalf("Failed to generate project: %v", err)
+			}
+
+			if len(rec.events) == 0 || rec.events[0].Type != events.Plan {
+				t.Fatalf("Expected a plan event first, got %+v", rec.events)
+			}
+			plan := rec.events[0]
+
+			var dirs, files []string
+			for _, e := range rec.events[1:] {
+				switch e.Type {
+				case events.DirCreated:
+					dirs = append(dirs, e.Path)
+				case events.FileWritten:
+					if e.Template == "" && filepath.Base(e.Path) != project.ManifestFile {
+						t.Errorf("Expected a template for %s", e.Path)
+					}
+					files = append(files, e.Path)
+				default:
+					t.Errorf("Unexpected event %s", e.Type)
+				}
+			}
+
+			if len(dirs) != len(plan.Dirs) {
+				t.Errorf("Expected %d dir_created events, got %d", len(plan.Dirs), len(dirs))
+			}
+			if len(files) != len(plan.Files) {
+				t.Fatalf("Expected %d file_written events, got %d", len(plan.Files), len(files))
+			}
+			for i, path := range plan.Files {
+				if want := filepath.Join(goldenName, path); files[i] != want {
+					t.Errorf("Expected file %d to be %s, got %s", i, want, files[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEventSinks(t *testing.T) {
+	stream := []events.Event{
+		{Type: events.Plan, Files: []string{"go.mod"}},
+		{Type: events.DirCreated, Path: "demo/cmd"},
+		{Type: events.FileWritten, Path: "demo/go.mod", Template: "cli/go-mod.tpl", DurationMS: 0.5},
+		{Type: events.Warning, Message: "careful"},
+		{Type: events.Error, Message: "failed"},
+		{Type: events.Done, Project: "demo", Message: "Project demo created successfully!", Steps: []string{"cd demo"}},
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		sink := events.NewJSONSink(&buf)
+		for _, e := range stream {
+			sink.Emit(e)
+		}
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != len(stream) {
+			t.Fatalf("Expected %d lines, got %d: %q", len(stream), len(lines), buf.String())
+		}
+		for i, line := range lines {
+			var got map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &got); err != nil {
+				t.Fatalf("Failed to parse event %q: %v", line, err)
+			}
+			if got["type"] != string(stream[i].Type) {
+				t.Errorf("Expected type %s, got %v", stream[i].Type, got["type"])
+			}
+			if got["time"] == nil {
+				t.Errorf("Expected a time in %q", line)
+			}
+		}
+		if !strings.Contains(lines[2], `"duration_ms":0.5`) {
+			t.Errorf("Expected a duration in %q", lines[2])
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		sink := events.NewTextSink(&out, &errOut)
+		for _, e := range stream {
+			sink.Emit(e)
+		}
+
+		want := "Created directory: demo/cmd\nCreated file: demo/go.mod\n\nProject demo created successfully!\n\nNext steps:\ncd demo\n"
+		if out.String() != want {
+			t.Errorf("Expected output %q, got %q", want, out.String())
+		}
+		if errOut.String() != "Warning: careful\n" {
+			t.Errorf("Expected the warning on errOut, got %q", errOut.String())
+		}
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		if _, err := events.ParseFormat("yaml"); err == nil {
+			t.Error("Expected an error for an unknown output format")
+		}
+	})
+}
+
+func TestInitJSONOutput(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "sova.yaml")
+	if err := os.WriteFile(configFile, []byte("defaults:\n  goVersion: \"1.21\"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if output, err := runSova(t, dir, "presets", "save", "quiet", "--template", "cli", "--set", "Logger=slog", "--config", configFile); err != nil {
+		t.Fatalf("Failed to save preset: %v\n%s", err, output)
+	}
+
+	cmd := exec.Command(sovaBinary, "init", "cli", "demo", "--preset", "quiet", "--config", configFile, "--output", "json")
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Failed to run sova init: %v\n%s", err, stderr.String())
+	}
+
+	var types []events.Type
+	var done events.Event
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		var e events.Event
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("Expected only JSON events on stdout, got %q: %v", line, err)
+		}
+		types = append(types, e.Type)
+		done = e
+	}
+
+	if types[0] != events.Plan {
+		t.Errorf("Expected the first event to be plan, got %s", types[0])
+	}
+	if done.Type != events.Done || done.Project != "demo" || done.Path != "demo" || len(done.Steps) == 0 {
+		t.Errorf("Unexpected last event: %+v", done)
+	}
+
+	cmd = exec.Command(sovaBinary, "init", "cli", "demo", "--preset", "quiet", "--config", configFile, "--output", "json")
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err == nil {
+		t.Fatal("Expected an error for an existing project directory")
+	}
+	var failed events.Event
+	if err := json.Unmarshal(bytes.TrimSpace(out), &failed); err != nil || failed.Type != events.Error {
+		t.Errorf("Expected a single error event, got %q", out)
+	}
+
+	// Failures before generation starts end the run the same way.
+	cmd = exec.Command(sovaBinary, "init", "other", "--preset", "missing", "--config", configFile, "--output", "json")
+	cmd.Dir = dir
+	out, err = cmd.Output()
+	if err == nil {
+		t.Fatal("Expected an error for an unknown preset")
+	}
+	failed = events.Event{}
+	if err := json.Unmarshal(bytes.TrimSpace(out), &failed); err != nil || failed.Type != events.Error || failed.Project != "other" {
+		t.Errorf("Expected a single error event, got %q", out)
+	}
+}
@@ -0,0 +1,555 @@
+package tests
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/build/constraint"
+	"go/format"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/project"
+	_ "github.com/go-sova/sova-cli/internal/project/api"
+	_ "github.com/go-sova/sova-cli/internal/project/cli"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+const (
+	goldenDir    = "testdata/golden"
+	stubsDir     = "testdata/stubs"
+	goldenName   = "demo"
+	goldenSuffix = ".golden"
+)
+
+// projectFiles maps slash-separated project-relative paths to contents.
+type projectFiles map[string][]byte
+
+type renderCase struct {
+	projectType string
+	name        string
+	answers     *questions.ProjectAnswers
+}
+
+// answerCombinations enumerates every combination of the bool questions
+// of every project type, with other questions at their defaults, then
+// every other option of each select question and nothing selected for
+// each multiselect question, with the remaining answers at their
+// defaults.
+func answerCombinations() ([]renderCase, error) {
+	var cases []renderCase
+
+	for _, projectType := range project.ProjectTypes() {
+		qs, err := questions.ProjectQuestions(templates.TemplateFS, projectType)
+		if err != nil {
+			return nil, err
+		}
+
+		var flags []string
+		for _, q := range qs {
+			if q.Type == questions.TypeBool {
+				flags = append(flags, q.Name)
+			}
+		}
+
+		for mask := 0; mask < 1<<len(flags); mask++ {
+			values := map[string]interface{}{}
+			var enabled []string
+			for i, name := range flags {
+				on := mask&(1<<i) != 0
+				values[name] = on
+				if on {
+					enabled = append(enabled, strings.ToLower(strings.TrimPrefix(name, "Use")))
+				}
+			}
+
+			name := "minimal"
+			if len(enabled) > 0 {
+				name = strings.Join(enabled, "-")
+			}
+
+			c, err := newRenderCase(projectType, name, values)
+			if err != nil {
+				return nil, err
+			}
+			cases = append(cases, c)
+		}
+
+		for _, q := range qs {
+			if q.Type == questions.TypeMultiSelect {
+				c, err := newRenderCase(projectType, strings.ToLower(q.Name)+"-none", map[string]interface{}{q.Name: []string{}})
+				if err != nil {
+					return nil, err
+				}
+				cases = append(cases, c)
+				continue
+			}
+			if q.Type != questions.TypeSelect {
+				continue
+			}
+			for _, option := range q.Options {
+				if option == q.Default {
+					continue
+				}
+				name := strings.ToLower(q.Name) + "-" + option
+				c, err := newRenderCase(projectType, name, map[string]interface{}{q.Name: option})
+				if err != nil {
+					return nil, err
+				}
+				cases = append(cases, c)
+			}
+		}
+	}
+
+	return cases, nil
+}
+
+func newRenderCase(projectType, name string, values map[string]interface{}) (renderCase, error) {
+	answers, err := questions.ResolveAnswers(templates.TemplateFS, projectType, values)
+	if err != nil {
+		return renderCase{}, err
+	}
+	answers.ProjectName = goldenName
+	return renderCase{projectType: projectType, name: name, answers: answers}, nil
+}
+
+func newGenerator(t *testing.T, c renderCase) project.Generator {
+	t.Helper()
+
+	gen, err := project.NewGenerator(c.projectType, goldenName, goldenName, c.answers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen.SetConfig(testConfig())
+	return gen
+}
+
+// testConfig returns the built-in configuration without the user's
+// template overrides, so golden files do not depend on the machine.
+func testConfig() *config.Config {
+	cfg := config.Default()
+	cfg.Defaults.GoVersion = "1.21"
+	cfg.Templates.Directory = ""
+	return cfg
+}
+
+// renderProject generates a project into an in-memory filesystem and
+// returns the files it contains.
+func renderProject(gen project.Generator) (projectFiles, error) {
+	out := vfs.NewMemFS()
+	gen.SetFS(out)
+	gen.SetOutput(io.Discard)
+
+	if err := project.Run(gen); err != nil {
+		return nil, err
+	}
+
+	rendered := projectFiles{}
+	err := fs.WalkDir(out, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := fs.ReadFile(out, name)
+		rendered[name] = data
+		return err
+	})
+	return rendered, err
+}
+
+func TestGoldenProjects(t *testing.T) {
+	checker := newTypeChecker()
+
+	cases, err := answerCombinations()
+	if err != nil {
+		t.Fatalf("Failed to enumerate answers: %v", err)
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.projectType+"/"+c.name, func(t *testing.T) {
+			got, err := renderProject(newGenerator(t, c))
+			if err != nil {
+				t.Fatalf("render failed: %v", err)
+			}
+
+			dir := filepath.Join(goldenDir, c.projectType, c.name)
+			if *update {
+				if err := writeGolden(dir, got); err != nil {
+					t.Fatalf("failed to update golden files: %v", err)
+				}
+			} else {
+				compareGolden(t, dir, got)
+			}
+
+			if err := checkFormatted(got); err != nil {
+				t.Fatal(err)
+			}
+			if err := checker.check(got); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+// checkFormatted fails for the first Go file in files that gofmt would
+// change.
+func checkFormatted(files projectFiles) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		if path.Ext(name) == ".go" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		formatted, err := format.Source(files[name])
+		if err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		if !bytes.Equal(formatted, files[name]) {
+			return fmt.Errorf("%s is not gofmt-clean\n%s", name, firstDifference(formatted, files[name]))
+		}
+	}
+	return nil
+}
+
+func writeGolden(dir string, files projectFiles) error {
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	for name, data := range files {
+		target := filepath.Join(dir, filepath.FromSlash(name)+goldenSuffix)
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func compareGolden(t *testing.T, dir string, got projectFiles) {
+	t.Helper()
+
+	want := map[string][]byte{}
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		want[strings.TrimSuffix(filepath.ToSlash(rel), goldenSuffix)] = data
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to read golden files (run with -update to create them): %v", err)
+	}
+
+	for name := range want {
+		if _, ok := got[name]; !ok {
+			t.Errorf("%s: expected file was not generated", name)
+		}
+	}
+
+	for name, data := range got {
+		expected, ok := want[name]
+		if !ok {
+			t.Errorf("%s: unexpected file was generated", name)
+			continue
+		}
+		if !bytes.Equal(data, expected) {
+			t.Errorf("%s: content mismatch\n%s", name, firstDifference(expected, data))
+		}
+	}
+}
+
+func firstDifference(want, got []byte) string {
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d\nwant: %q\ngot:  %q", i+1, w, g)
+		}
+	}
+
+	return "files differ"
+}
+
+// typeChecker parses and type-checks generated projects. Standard library
+// packages are checked from source, third-party imports resolve to the
+// hand-written stubs in testdata/stubs.
+type typeChecker struct {
+	fset  *token.FileSet
+	std   types.Importer
+	stubs map[string]*types.Package
+}
+
+func newTypeChecker() *typeChecker {
+	// Generated projects never use cgo; checking the standard library
+	// without it avoids invoking the cgo tool from the importer.
+	build.Default.CgoEnabled = false
+
+	fset := token.NewFileSet()
+	return &typeChecker{
+		fset:  fset,
+		std:   importer.ForCompiler(fset, "source", nil),
+		stubs: map[string]*types.Package{},
+	}
+}
+
+// parseProject parses every Go file in files and groups those whose build
+// constraints are satisfied by package.
+func (c *typeChecker) parseProject(files projectFiles) (map[string][]*ast.File, error) {
+	pkgs := map[string][]*ast.File{}
+
+	for name, data := range files {
+		if path.Ext(name) != ".go" {
+			continue
+		}
+		f, err := parser.ParseFile(c.fset, name, data, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		ok, err := buildable(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		if !ok {
+			continue
+		}
+		// An external test package is checked like a package of its own
+		// next to the one it tests
+		dir := path.Dir(name)
+		if strings.HasSuffix(f.Name.Name, "_test") {
+			dir += "_test"
+		}
+		pkgs[dir] = append(pkgs[dir], f)
+	}
+
+	return pkgs, nil
+}
+
+// buildable reports whether the //go:build line of f, if any, is satisfied
+// with the integration build tag, so the integration tests of generated
+// projects are checked too.
+func buildable(f *ast.File) (bool, error) {
+	for _, group := range f.Comments {
+		if group.Pos() > f.Package {
+			break
+		}
+		for _, comment := range group.List {
+			if !constraint.IsGoBuild(comment.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(comment.Text)
+			if err != nil {
+				return false, err
+			}
+			return expr.Eval(func(tag string) bool {
+				return tag == "integration" || tag == runtime.GOOS || tag == runtime.GOARCH || tag == "gc" || strings.HasPrefix(tag, "go1.")
+			}), nil
+		}
+	}
+	return true, nil
+}
+
+func (c *typeChecker) check(files projectFiles) error {
+	module, goVersion, requires, err := readGoMod(files)
+	if err != nil {
+		return err
+	}
+
+	pkgs, err := c.parseProject(files)
+	if err != nil {
+		return err
+	}
+
+	imp := &projectImporter{
+		checker:   c,
+		module:    module,
+		goVersion: goVersion,
+		requires:  requires,
+		files:     pkgs,
+		checked:   map[string]*types.Package{},
+	}
+
+	dirs := make([]string, 0, len(pkgs))
+	for dir := range pkgs {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	for _, dir := range dirs {
+		if _, err := imp.Import(path.Join(module, dir)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importStub type-checks the stub for importPath, falling back to the
+// standard library when no stub exists.
+func (c *typeChecker) importStub(importPath string) (*types.Package, error) {
+	if pkg, ok := c.stubs[importPath]; ok {
+		return pkg, nil
+	}
+
+	dir := filepath.Join(stubsDir, filepath.FromSlash(importPath))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) && !strings.Contains(strings.Split(importPath, "/")[0], ".") {
+			return c.std.Import(importPath)
+		}
+		return nil, fmt.Errorf("no stub for %s: %w", importPath, err)
+	}
+
+	var files []*ast.File
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".go" {
+			continue
+		}
+		f, err := parser.ParseFile(c.fset, filepath.Join(dir, entry.Name()), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, f)
+	}
+
+	conf := types.Config{Importer: importerFunc(c.importStub)}
+	pkg, err := conf.Check(importPath, c.fset, files, nil)
+	if err != nil {
+		return nil, fmt.Errorf("stub %s: %w", importPath, err)
+	}
+
+	c.stubs[importPath] = pkg
+	return pkg, nil
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) { return f(path) }
+
+// projectImporter resolves imports of the generated module to its own
+// packages and everything else to stubs or the standard library.
+type projectImporter struct {
+	checker   *typeChecker
+	module    string
+	goVersion string
+	requires  []string
+	files     map[string][]*ast.File
+	checked   map[string]*types.Package
+}
+
+func (p *projectImporter) Import(importPath string) (*types.Package, error) {
+	if pkg, ok := p.checked[importPath]; ok {
+		if pkg == nil {
+			return nil, fmt.Errorf("import cycle through %s", importPath)
+		}
+		return pkg, nil
+	}
+
+	if importPath != p.module && !strings.HasPrefix(importPath, p.module+"/") {
+		if strings.Contains(strings.Split(importPath, "/")[0], ".") && !p.required(importPath) {
+			return nil, fmt.Errorf("%s is imported but go.mod does not require its module", importPath)
+		}
+		return p.checker.importStub(importPath)
+	}
+
+	dir := strings.TrimPrefix(strings.TrimPrefix(importPath, p.module), "/")
+	if dir == "" {
+		dir = "."
+	}
+	files, ok := p.files[dir]
+	if !ok {
+		return nil, fmt.Errorf("package %s is not part of the generated project", importPath)
+	}
+
+	p.checked[importPath] = nil
+	conf := types.Config{Importer: p, GoVersion: p.goVersion}
+	pkg, err := conf.Check(importPath, p.checker.fset, files, nil)
+	if err != nil {
+		return nil, err
+	}
+	p.checked[importPath] = pkg
+	return pkg, nil
+}
+
+// required reports whether importPath belongs to a module go.mod requires.
+func (p *projectImporter) required(importPath string) bool {
+	for _, module := range p.requires {
+		if importPath == module || strings.HasPrefix(importPath, module+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// readGoMod extracts the module path, go directive and required modules
+// of a generated project.
+func readGoMod(files projectFiles) (module, goVersion string, requires []string, err error) {
+	data, ok := files["go.mod"]
+	if !ok {
+		return "", "", nil, fmt.Errorf("generated project has no go.mod")
+	}
+
+	inRequire := false
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case inRequire && len(fields) == 1 && fields[0] == ")":
+			inRequire = false
+		case inRequire && len(fields) >= 2:
+			requires = append(requires, fields[0])
+		case len(fields) == 2 && fields[0] == "require" && fields[1] == "(":
+			inRequire = true
+		case len(fields) >= 3 && fields[0] == "require":
+			requires = append(requires, fields[1])
+		case len(fields) == 2 && fields[0] == "module":
+			module = fields[1]
+		case len(fields) == 2 && fields[0] == "go":
+			goVersion = "go" + fields[1]
+		}
+	}
+
+	if module == "" {
+		return "", "", nil, fmt.Errorf("go.mod does not declare a module")
+	}
+	return module, goVersion, requires, nil
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/goversion"
+	"github.com/go-sova/sova-cli/internal/project"
+)
+
+func TestGoVersionParse(t *testing.T) {
+	tests := []struct {
+		input     string
+		directive string
+		toolchain string
+		language  string
+	}{
+		{"1.22", "1.22.0", "", "1.22"},
+		{"1.22.3", "1.22.0", "go1.22.3", "1.22"},
+		{"go1.23.1", "1.23.0", "go1.23.1", "1.23"},
+		{"go1.24rc1", "1.24.0", "", "1.24"},
+		{"1.20.5", "1.20", "", "1.20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := goversion.Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Failed to parse %s: %v", tt.input, err)
+			}
+			if got := v.Directive(); got != tt.directive {
+				t.Errorf("Directive() = %q, want %q", got, tt.directive)
+			}
+			if got := v.Toolchain(); got != tt.toolchain {
+				t.Errorf("Toolchain() = %q, want %q", got, tt.toolchain)
+			}
+			if got := v.Language(); got != tt.language {
+				t.Errorf("Language() = %q, want %q", got, tt.language)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "1", "latest", "1.22.x"} {
+		if _, err := goversion.Parse(bad); err == nil {
+			t.Errorf("Expected an error parsing %q", bad)
+		}
+	}
+}
+
+func TestResolveGoVersion(t *testing.T) {
+	v, warnings, err := project.ResolveGoVersion("api", "1.22.5")
+	if err != nil {
+		t.Fatalf("Failed to resolve go version: %v", err)
+	}
+	if v.String() != "1.22.5" || len(warnings) != 0 {
+		t.Errorf("Expected 1.22.5 without warnings, got %s %v", v, warnings)
+	}
+
+	_, warnings, err = project.ResolveGoVersion("api", "1.19")
+	if err != nil {
+		t.Fatalf("Failed to resolve go version: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "older than Go 1.21") {
+		t.Errorf("Expected a warning about the template minimum, got %v", warnings)
+	}
+
+	if _, _, err := project.ResolveGoVersion("api", "latest"); err == nil {
+		t.Error("Expected an error for an invalid go version")
+	}
+
+	if _, err := goversion.Local(); err != nil {
+		t.Skipf("Go toolchain not detected: %v", err)
+	}
+	if _, warnings, err := project.ResolveGoVersion("cli", ""); err != nil || len(warnings) != 0 {
+		t.Errorf("Expected the local toolchain to resolve cleanly, got %v %v", warnings, err)
+	}
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-sova/sova-cli/pkg/utils"
+)
+
+func TestLoggerFormats(t *testing.T) {
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := utils.NewLoggerWithPrefix(utils.Info, "Test")
+		logger.SetOutput(&buf)
+		logger.SetFormat(utils.JSONFormat)
+
+		logger.Info("hello %s", "world")
+
+		var entry map[string]string
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("Failed to parse log entry %q: %v", buf.String(), err)
+		}
+		want := map[string]string{"level": "info", "component": "Test", "msg": "hello world"}
+		for key, value := range want {
+			if entry[key] != value {
+				t.Errorf("Expected %s %q, got %q", key, value, entry[key])
+			}
+		}
+		if entry["time"] == "" {
+			t.Error("Expected a time field")
+		}
+	})
+
+	t.Run("Text", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := utils.NewLoggerWithPrefix(utils.Info, "Test")
+		logger.SetOutput(&buf)
+
+		logger.Warning("careful")
+		if !strings.Contains(buf.String(), "[Test] [WARNING] careful") {
+			t.Errorf("Unexpected log line: %q", buf.String())
+		}
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		if _, err := utils.ParseLogFormat("xml"); err == nil {
+			t.Error("Expected an error for an unknown log format")
+		}
+	})
+}
+
+func TestLoggerLevels(t *testing.T) {
+	var buf bytes.Buffer
+	logger := utils.NewLogger(utils.Warning)
+	logger.SetOutput(&buf)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warning("warning")
+	logger.Error("error")
+
+	out := buf.String()
+	for _, msg := range []string{"debug", "info"} {
+		if strings.Contains(out, "] "+msg) {
+			t.Errorf("Expected %s message to be filtered, got %q", msg, out)
+		}
+	}
+	for _, msg := range []string{"warning", "error"} {
+		if !strings.Contains(out, "] "+msg) {
+			t.Errorf("Expected %s message, got %q", msg, out)
+		}
+	}
+}
+
+func TestLoggerFatalReturnsError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := utils.NewLogger(utils.Info)
+	logger.SetOutput(&buf)
+
+	err := logger.Fatal("cannot continue: %d", 42)
+	if err == nil || err.Error() != "cannot continue: 42" {
+		t.Errorf("Expected error %q, got %v", "cannot continue: 42", err)
+	}
+	if !strings.Contains(buf.String(), "[FATAL] cannot continue: 42") {
+		t.Errorf("Expected the fatal message to be logged, got %q", buf.String())
+	}
+}
+
+func TestConfigureLogging(t *testing.T) {
+	prev := utils.LoggingOptions()
+	t.Cleanup(func() { utils.ConfigureLogging(prev) })
+
+	var buf bytes.Buffer
+	utils.ConfigureLogging(utils.LoggerOptions{Level: utils.Debug, Format: utils.JSONFormat, Output: &buf})
+
+	utils.NewComponentLogger("Generator").Debug("component message")
+	utils.DefaultLogger.Debug("default message")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 log entries, got %q", buf.String())
+	}
+	for i, want := range []string{"component message", "default message"} {
+		var entry map[string]string
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err != nil {
+			t.Fatalf("Failed to parse log entry %q: %v", lines[i], err)
+		}
+		if entry["level"] != "debug" || entry["msg"] != want {
+			t.Errorf("Unexpected log entry: %q", lines[i])
+		}
+	}
+}
+
+func TestLogFileVerboseEnv(t *testing.T) {
+	configFile, err := filepath.Abs("testdata/sova.yaml")
+	if err != nil {
+		t.Fatalf("Failed to resolve config file: %v", err)
+	}
+	logFile := filepath.Join(t.TempDir(), "sova.log")
+
+	cmd := exec.Command(sovaBinary, "--config", configFile, "--log-file", logFile, "version")
+	cmd.Env = append(os.Environ(), "SOVA_VERBOSE=1")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to run sova version: %v\n%s", err, output)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("Failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "Using config file: "+configFile) {
+		t.Errorf("Expected SOVA_VERBOSE to log debug messages to the log file, got %q", data)
+	}
+}
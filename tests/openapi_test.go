@@ -0,0 +1,569 @@
+package tests
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/openapi"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/internal/project/api"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+)
+
+const petstoreSpec = "testdata/openapi/petstore.yaml"
+
+func loadPetstore(t *testing.T) *openapi.Spec {
+	t.Helper()
+
+	spec, err := openapi.LoadFile(petstoreSpec)
+	if err != nil {
+		t.Fatalf("Failed to load spec: %v", err)
+	}
+	return spec
+}
+
+// readProject returns the files of the project in dir.
+func readProject(t *testing.T, dir string) projectFiles {
+	t.Helper()
+
+	files := projectFiles{}
+	err := fs.WalkDir(os.DirFS(dir), ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		files[name] = data
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to read project: %v", err)
+	}
+	return files
+}
+
+func TestOpenAPIProject(t *testing.T) {
+	c, err := newRenderCase("api", "openapi", nil)
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	gen := api.NewAPIProjectGenerator(goldenName, goldenName, c.answers)
+	gen.SetConfig(testConfig())
+	gen.SetOpenAPI(loadPetstore(t))
+
+	got, err := renderProject(gen)
+	if err != nil {
+		t.Fatalf("Failed to render project: %v", err)
+	}
+
+	dir := filepath.Join(goldenDir, "openapi", "petstore")
+	if *update {
+		if err := writeGolden(dir, got); err != nil {
+			t.Fatalf("Failed to update golden files: %v", err)
+		}
+	} else {
+		compareGolden(t, dir, got)
+	}
+
+	if err := checkFormatted(got); err != nil {
+		t.Fatal(err)
+	}
+	if err := newTypeChecker().check(got); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, _, err := project.ReadManifest(memFiles(got))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	if manifest.OpenAPI != "api/openapi.yaml" {
+		t.Errorf("Expected the manifest to record api/openapi.yaml, got %q", manifest.OpenAPI)
+	}
+}
+
+// TestOpenAPIContainers checks that the wire and fx containers build the
+// API of the spec and pass it to the routes, as TestOpenAPIProject checks
+// for the manual one.
+func TestOpenAPIContainers(t *testing.T) {
+	for _, di := range []string{"wire", "fx"} {
+		t.Run(di, func(t *testing.T) {
+			c, err := newRenderCase("api", "openapi-"+di, map[string]interface{}{"DI": di})
+			if err != nil {
+				t.Fatalf("Failed to resolve answers: %v", err)
+			}
+			gen := api.NewAPIProjectGenerator(goldenName, goldenName, c.answers)
+			gen.SetConfig(testConfig())
+			gen.SetOpenAPI(loadPetstore(t))
+
+			got, err := renderProject(gen)
+			if err != nil {
+				t.Fatalf("Failed to render project: %v", err)
+			}
+			if err := checkFormatted(got); err != nil {
+				t.Fatal(err)
+			}
+			if err := newTypeChecker().check(got); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Contains(got["internal/routes/routes.go"], []byte("openapi.RegisterRoutes(router, deps.API)")) {
+				t.Error("Expected the routes to register the API of the dependencies")
+			}
+		})
+	}
+}
+
+// memFiles returns files as an in-memory filesystem.
+func memFiles(files projectFiles) fs.FS {
+	m := vfs.NewMemFS()
+	for name, data := range files {
+		m.MkdirAll(path.Dir(name), 0755)
+		m.WriteFile(name, data, 0644)
+	}
+	return m
+}
+
+func TestAddOpenAPIKeepsHandlers(t *testing.T) {
+	c, err := newRenderCase("api", "openapi", nil)
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), goldenName)
+	gen := newGenerator(t, c)
+	gen.SetFS(vfs.NewOSFS(dir))
+	gen.SetEvents(events.Discard)
+	if err := project.Run(gen); err != nil {
+		t.Fatalf("Failed to generate project: %v", err)
+	}
+
+	sink := &recorder{}
+	if err := api.AddOpenAPI(dir, petstoreSpec, sink); err != nil {
+		t.Fatalf("Failed to add spec: %v", err)
+	}
+	if !hasWarning(sink, "does not register the operations") {
+		t.Errorf("Expected a warning that routes.go does not register the operations")
+	}
+
+	// Implement an operation, then drop one and add one to the spec
+	handler := filepath.Join(dir, "internal", "handlers", "get_pet.go")
+	editFile(t, handler, func(s string) string {
+		return strings.Replace(s, `return resp, apperror.New(http.StatusNotImplemented, "getPet is not implemented")`,
+			`if req.PetID != 1 {
+		return resp, apperror.New(http.StatusNotFound, "pet not found")
+	}
+	return openapi.Pet{ID: req.PetID, Name: "Rex"}, nil`, 1)
+	})
+	editFile(t, filepath.Join(dir, "internal", "handlers", "delete_pet.go"), func(s string) string {
+		return strings.Replace(s, "http.StatusNotImplemented", "http.StatusNotFound", 1)
+	})
+	implemented, err := os.ReadFile(handler)
+	if err != nil {
+		t.Fatalf("Failed to read handler: %v", err)
+	}
+	if !strings.Contains(string(implemented), "Rex") {
+		t.Fatalf("Failed to implement the stub:\n%s", implemented)
+	}
+	editFile(t, filepath.Join(dir, "api", "openapi.yaml"), func(s string) string {
+		s = strings.Replace(s, "    delete:\n      operationId: deletePet", "    put:\n      operationId: updatePet", 1)
+		return strings.Replace(s, `"204":
+          description: The pet was deleted.`, `"200":
+          description: The updated pet.
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/Pet"`, 1)
+	})
+
+	sink = &recorder{}
+	if err := api.AddOpenAPI(dir, "", sink); err != nil {
+		t.Fatalf("Failed to regenerate: %v", err)
+	}
+
+	got, err := os.ReadFile(handler)
+	if err != nil {
+		t.Fatalf("Failed to read handler: %v", err)
+	}
+	if string(got) != string(implemented) {
+		t.Errorf("Expected the implemented handler to be kept, got:\n%s", got)
+	}
+	for _, name := range []string{"update_pet.go", "update_pet_test.go"} {
+		if _, err := os.Stat(filepath.Join(dir, "internal", "handlers", name)); err != nil {
+			t.Errorf("Expected a stub and its test for the new operation: %v", err)
+		}
+	}
+	if !hasWarning(sink, "API.DeletePet") {
+		t.Errorf("Expected a warning about the handler of the removed operation")
+	}
+	for _, e := range sink.events {
+		if e.Type == events.FileWritten && strings.HasSuffix(e.Path, filepath.Join("internal", "handlers", "api.go")) {
+			t.Errorf("Expected api.go to be left alone")
+		}
+	}
+
+	// Once the handler of the removed operation and its test are gone, the
+	// project builds again
+	for _, name := range []string{"delete_pet.go", "delete_pet_test.go"} {
+		if err := os.Remove(filepath.Join(dir, "internal", "handlers", name)); err != nil {
+			t.Fatalf("Failed to remove handler: %v", err)
+		}
+	}
+	updated := readProject(t, dir)
+	if err := checkFormatted(updated); err != nil {
+		t.Fatal(err)
+	}
+	if err := newTypeChecker().check(updated); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func hasWarning(r *recorder, text string) bool {
+	for _, e := range r.events {
+		if e.Type == events.Warning && strings.Contains(e.Message, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestAddOpenAPINeedsAPIProject(t *testing.T) {
+	c, err := newRenderCase("cli", "openapi", nil)
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), goldenName)
+	gen := newGenerator(t, c)
+	gen.SetFS(vfs.NewOSFS(dir))
+	gen.SetEvents(events.Discard)
+	if err := project.Run(gen); err != nil {
+		t.Fatalf("Failed to generate project: %v", err)
+	}
+
+	err = api.AddOpenAPI(dir, petstoreSpec, events.Discard)
+	if err == nil || !strings.Contains(err.Error(), "only be added to api projects") {
+		t.Errorf("Expected an error for a cli project, got %v", err)
+	}
+}
+
+func TestLoadOpenAPIErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "Swagger 2",
+			spec: "swagger: \"2.0\"\ninfo: {title: x, version: \"1\"}\npaths: {}\n",
+			err:  "swagger 2.0 documents are not supported",
+		},
+		{
+			name: "No operations",
+			spec: "openapi: 3.0.3\ninfo: {title: x, version: \"1\"}\npaths: {}\n",
+			err:  "defines no operations",
+		},
+		{
+			name: "External reference",
+			spec: `openapi: 3.0.3
+info: {title: x, version: "1"}
+paths:
+  /pets:
+    get:
+      responses:
+        "200":
+          description: ok
+          content:
+            application/json:
+              schema:
+                $ref: "pets.yaml#/Pet"
+`,
+			err: "pets.yaml#/Pet",
+		},
+		{
+			name: "Undeclared path parameter",
+			spec: `openapi: 3.0.3
+info: {title: x, version: "1"}
+paths:
+  /pets/{petId}:
+    get:
+      responses:
+        "200": {description: ok}
+`,
+			err: "petId",
+		},
+		{
+			name: "Same Go name",
+			spec: `openapi: 3.0.3
+info: {title: x, version: "1"}
+paths:
+  /a:
+    get:
+      operationId: list_pets
+      responses: {"200": {description: ok}}
+  /b:
+    get:
+      operationId: listPets
+      responses: {"200": {description: ok}}
+`,
+			err: "same Go name ListPets",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := openapi.Load([]byte(tc.spec))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Expected an error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestGenerateOpenAPIErrors(t *testing.T) {
+	testCases := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "XML body",
+			spec: `openapi: 3.0.3
+info: {title: x, version: "1"}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/xml:
+            schema: {type: string}
+      responses: {"204": {description: ok}}
+`,
+			err: "media types application/xml are not supported",
+		},
+		{
+			name: "Object parameter",
+			spec: `openapi: 3.0.3
+info: {title: x, version: "1"}
+paths:
+  /pets:
+    get:
+      parameters:
+        - name: filter
+          in: query
+          schema:
+            type: object
+            properties:
+              tag: {type: string}
+      responses: {"204": {description: ok}}
+`,
+			err: "parameter filter",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spec, err := openapi.Load([]byte(tc.spec))
+			if err != nil {
+				t.Fatalf("Failed to load spec: %v", err)
+			}
+			_, err = openapi.Generate(spec, goldenName)
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Expected an error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+const exportHandlers = `package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Role is the role of a user.
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleMember Role = "member"
+)
+
+// User is a user of the service.
+type User struct {
+	ID        int64     ` + "`json:\"id\"`" + `
+	Name      string    ` + "`json:\"name\"`" + `
+	Role      Role      ` + "`json:\"role\"`" + `
+	Email     *string   ` + "`json:\"email,omitempty\"`" + `
+	CreatedAt time.Time ` + "`json:\"created_at\"`" + `
+	secret    string
+}
+
+// NewUser is the body of a request creating a user.
+type NewUser struct {
+	Name string ` + "`json:\"name\" binding:\"required\"`" + `
+	Role Role   ` + "`json:\"role,omitempty\"`" + `
+}
+
+// ListUsers returns the users, filtered by role.
+func ListUsers(c *gin.Context) {
+	_ = c.Query("role")
+	limit := c.DefaultQuery("limit", "10")
+	_ = limit
+	c.JSON(http.StatusOK, []User{})
+}
+
+// CreateUser creates a user.
+func CreateUser(c *gin.Context) {
+	var body NewUser
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, User{Name: body.Name, Role: body.Role})
+}
+
+// Users serves the users of a store.
+type Users struct{}
+
+// Get returns the user with the given ID.
+func (u *Users) Get(c *gin.Context) {
+	if c.GetHeader("X-Tenant") == "" {
+		c.AbortWithStatus(http.StatusForbidden)
+		return
+	}
+	c.JSON(http.StatusOK, &User{})
+}
+
+// DeleteUser returns a handler deleting users.
+func DeleteUser(store *Users) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		_ = c.Param("id")
+		c.Status(http.StatusNoContent)
+	}
+}
+`
+
+func TestExportOpenAPI(t *testing.T) {
+	c, err := newRenderCase("api", "export", nil)
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	dir := filepath.Join(t.TempDir(), goldenName)
+	gen := newGenerator(t, c)
+	gen.SetFS(vfs.NewOSFS(dir))
+	gen.SetEvents(events.Discard)
+	if err := project.Run(gen); err != nil {
+		t.Fatalf("Failed to generate project: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "internal", "handlers", "users.go"), []byte(exportHandlers), 0644); err != nil {
+		t.Fatalf("Failed to write handlers: %v", err)
+	}
+	editFile(t, filepath.Join(dir, "internal", "routes", "routes.go"), func(s string) string {
+		s = strings.Replace(s, "\treturn nil\n}", "\tregisterUsers(api.Group(\"/v2\"))\n\n\treturn nil\n}", 1)
+		return s + `
+func registerUsers(r *gin.RouterGroup) {
+	users := r.Group("/users")
+	store := &handlers.Users{}
+	users.GET("", handlers.ListUsers)
+	users.POST("", handlers.CreateUser)
+	users.GET("/:id", store.Get)
+	users.DELETE("/:id", handlers.DeleteUser(store))
+	users.Any("/legacy", handlers.ListUsers)
+}
+`
+	})
+	if err := newTypeChecker().check(readProject(t, dir)); err != nil {
+		t.Fatalf("Test project does not build: %v", err)
+	}
+
+	doc, warnings, err := openapi.Export(vfs.NewOSFS(dir), goldenName)
+	if err != nil {
+		t.Fatalf("Failed to export: %v", err)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "Any") {
+		t.Errorf("Expected one warning about the Any route, got %q", warnings)
+	}
+
+	// sova can generate from the exported document
+	spec, err := openapi.Load(doc)
+	if err != nil {
+		t.Fatalf("Exported document does not load: %v\n%s", err, doc)
+	}
+	if _, err := openapi.Generate(spec, goldenName); err != nil {
+		t.Fatalf("Failed to generate from exported document: %v\n%s", err, doc)
+	}
+
+	ops := map[string]*openapi.Operation{}
+	for _, op := range spec.Operations() {
+		ops[op.String()] = op
+	}
+	testCases := []struct {
+		op        string
+		id        string
+		params    []string
+		body      bool
+		responses []string
+	}{
+		{op: "GET /livez", id: "livez", responses: []string{"200"}},
+		{op: "GET /readyz", id: "readyz", responses: []string{"200", "503"}},
+		{op: "GET /api/ping", id: "ping", responses: []string{"200"}},
+		{op: "GET /api/v2/users", id: "listUsers", params: []string{"query role", "query limit"}, responses: []string{"200"}},
+		{op: "POST /api/v2/users", id: "createUser", body: true, responses: []string{"201", "400"}},
+		{op: "GET /api/v2/users/{id}", id: "usersGet", params: []string{"path id", "header X-Tenant"}, responses: []string{"200", "403"}},
+		{op: "DELETE /api/v2/users/{id}", id: "deleteUser", params: []string{"path id"}, responses: []string{"204"}},
+	}
+	if len(ops) != len(testCases) {
+		t.Errorf("Expected %d operations, got %d:\n%s", len(testCases), len(ops), doc)
+	}
+	for _, tc := range testCases {
+		t.Run(tc.op, func(t *testing.T) {
+			op, ok := ops[tc.op]
+			if !ok {
+				t.Fatalf("Operation not exported:\n%s", doc)
+			}
+			if op.OperationID != tc.id {
+				t.Errorf("Expected operationId %s, got %s", tc.id, op.OperationID)
+			}
+			var params []string
+			for _, p := range op.Parameters {
+				params = append(params, p.In+" "+p.Name)
+			}
+			if strings.Join(params, ",") != strings.Join(tc.params, ",") {
+				t.Errorf("Expected parameters %q, got %q", tc.params, params)
+			}
+			if (op.RequestBody != nil) != tc.body {
+				t.Errorf("Expected a request body: %v", tc.body)
+			}
+			if strings.Join(op.Responses.Keys, ",") != strings.Join(tc.responses, ",") {
+				t.Errorf("Expected responses %q, got %q", tc.responses, op.Responses.Keys)
+			}
+		})
+	}
+
+	if got := ops["GET /api/v2/users"].Summary; got != "Returns the users, filtered by role" {
+		t.Errorf("Expected the summary to come from the doc comment, got %q", got)
+	}
+
+	user := spec.Components.Schemas.Values["User"]
+	if user == nil {
+		t.Fatalf("Expected a User schema:\n%s", doc)
+	}
+	if got := strings.Join(user.Properties.Keys, ","); got != "id,name,role,email,created_at" {
+		t.Errorf("Expected the json names of the exported fields, got %s", got)
+	}
+	if got := strings.Join(user.Required, ","); got != "id,name,role,created_at" {
+		t.Errorf("Expected the fields without omitempty to be required, got %s", got)
+	}
+	if got := user.Properties.Values["created_at"].Format; got != "date-time" {
+		t.Errorf("Expected time.Time to be a date-time, got %q", got)
+	}
+	if role := spec.Components.Schemas.Values["Role"]; role == nil || len(role.Enum) != 2 {
+		t.Errorf("Expected Role to be an enum of its constants:\n%s", doc)
+	}
+}
@@ -0,0 +1,163 @@
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/config"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+// usePresetFile makes the current configuration read presets from a
+// fresh file for the duration of the test.
+func usePresetFile(t *testing.T) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), ".sova.yaml")
+	cfg := config.Default()
+	cfg.File = path
+	config.SetCurrent(cfg)
+	t.Cleanup(func() { config.SetCurrent(nil) })
+	return path
+}
+
+func TestPresetStorage(t *testing.T) {
+	path := usePresetFile(t)
+	if err := os.WriteFile(path, []byte("# team defaults\ndefaults:\n  author: Jane Doe\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+
+	presets := []config.Preset{
+		{Name: "team-api", Template: "api", Answers: map[string]interface{}{"UsePostgres": true, "UseRedis": true}},
+		{Name: "worker", Template: "api", Description: "RabbitMQ worker", Answers: map[string]interface{}{"UseRabbitMQ": true}},
+	}
+	for _, preset := range presets {
+		if err := config.SavePreset(path, preset); err != nil {
+			t.Fatalf("Failed to save preset %s: %v", preset.Name, err)
+		}
+	}
+
+	loaded, err := config.LoadPresets(path)
+	if err != nil {
+		t.Fatalf("Failed to load presets: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, presets) {
+		t.Errorf("Expected presets %+v, got %+v", presets, loaded)
+	}
+
+	if err := config.DeletePreset(path, "worker"); err != nil {
+		t.Fatalf("Failed to delete preset: %v", err)
+	}
+	if err := config.DeletePreset(path, "worker"); err == nil {
+		t.Error("Expected an error deleting a missing preset")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config file: %v", err)
+	}
+	for _, want := range []string{"# team defaults", "author: Jane Doe", "team-api:"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Expected %q in config file:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "worker") {
+		t.Errorf("Deleted preset is still in the config file:\n%s", data)
+	}
+
+	for _, bad := range []config.Preset{
+		{Name: "has space", Template: "api"},
+		{Name: "no-template"},
+	} {
+		if err := config.SavePreset(path, bad); err == nil {
+			t.Errorf("Expected an error saving %+v", bad)
+		}
+	}
+}
+
+func TestPresetAnswers(t *testing.T) {
+	path := usePresetFile(t)
+
+	info, err := templates.LoadInfo("api")
+	if err != nil {
+		t.Fatalf("Failed to load template metadata: %v", err)
+	}
+
+	for _, preset := range []config.Preset{
+		{Name: "pinned", Template: "api", TemplateVersion: info.Version, Answers: map[string]interface{}{"UseRedis": true}},
+		{Name: "stale", Template: "api", TemplateVersion: "0.0.1"},
+		{Name: "tool", Template: "cli"},
+		{Name: "ghost", Template: "worker"},
+		{Name: "legacy", Template: "api", Answers: map[string]interface{}{"UseZap": true, "UseRedis": true}},
+	} {
+		if err := config.SavePreset(path, preset); err != nil {
+			t.Fatalf("Failed to save preset %s: %v", preset.Name, err)
+		}
+	}
+
+	testCases := []struct {
+		preset      string
+		projectType string
+		err         string
+	}{
+		{preset: "pinned", projectType: "api"},
+		{preset: "legacy", projectType: "api"},
+		{preset: "stale", projectType: "api", err: "is pinned to api template version 0.0.1"},
+		{preset: "tool", projectType: "api", err: "is for cli projects"},
+		{preset: "ghost", projectType: "api", err: "unknown template"},
+		{preset: "missing", projectType: "api", err: "unknown preset"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.preset, func(t *testing.T) {
+			answers, err := project.PresetAnswers(tc.preset, tc.projectType)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Errorf("Expected error containing %q, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if answers["UseRedis"] != true {
+				t.Errorf("Expected the preset answers, got %v", answers)
+			}
+			if _, err := questions.ResolveAnswers(templates.TemplateFS, tc.projectType, answers); err != nil {
+				t.Errorf("Failed to resolve preset answers: %v", err)
+			}
+		})
+	}
+
+	// UseZap was replaced by the Logger select.
+	answers, err := project.PresetAnswers("legacy", "api")
+	if err != nil {
+		t.Fatalf("Failed to load legacy preset: %v", err)
+	}
+	if _, ok := answers["UseZap"]; ok || answers["Logger"] != "zap" {
+		t.Errorf("Expected UseZap to become Logger: zap, got %v", answers)
+	}
+}
+
+func TestAskMissingQuestionsUsesKnownAnswers(t *testing.T) {
+	known := map[string]interface{}{"Logger": "zerolog", "UsePostgres": false, "UseRedis": true, "UseRabbitMQ": true, "UseMetrics": true, "UseTracing": true, "Auth": "apikey", "Middleware": []string{"cors", "ratelimit"}, "DI": "fx"}
+
+	// Every question is answered, so nothing is prompted for.
+	answers, err := questions.AskMissingQuestions(templates.TemplateFS, "api", known)
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	if !reflect.DeepEqual(map[string]interface{}(answers.Values), known) {
+		t.Errorf("Expected answers %v, got %v", known, answers.Values)
+	}
+
+	known["UseKafka"] = true
+	if _, err := questions.AskMissingQuestions(templates.TemplateFS, "api", known); err == nil {
+		t.Error("Expected an error for an unknown answer")
+	}
+}
@@ -0,0 +1,302 @@
+package tests
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+const testSchema = `
+questions:
+  - name: UseGRPC
+    type: bool
+    message: Enable gRPC?
+  - name: UseGateway
+    type: bool
+    message: Enable gRPC gateway?
+    default: true
+    when: .UseGRPC
+  - name: Port
+    type: int
+    message: Which port should the server listen on?
+    default: 8080
+  - name: Owner
+    type: string
+    message: Who owns the service?
+    default: platform
+    validate: ^[a-z]+$
+  - name: Logger
+    type: select
+    message: Which logger?
+    options: [slog, zap]
+  - name: ZapSampling
+    type: bool
+    message: Sample zap logs?
+    when: eq .Logger "zap"
+  - name: Middleware
+    type: multiselect
+    message: Which middleware?
+    options: [cors, recover, requestid]
+    default: [recover]
+`
+
+func TestParseSchema(t *testing.T) {
+	schema, err := questions.ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	defaults := map[string]interface{}{}
+	for _, q := range schema.Questions {
+		defaults[q.Name] = q.Default
+	}
+
+	want := map[string]interface{}{
+		"UseGRPC":     false,
+		"UseGateway":  true,
+		"Port":        8080,
+		"Owner":       "platform",
+		"Logger":      "slog",
+		"ZapSampling": false,
+		"Middleware":  []string{"recover"},
+	}
+	if !reflect.DeepEqual(defaults, want) {
+		t.Errorf("Expected defaults %v, got %v", want, defaults)
+	}
+}
+
+func TestParseSchemaErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{name: "unknown type", schema: "questions:\n  - {name: A, type: float, message: Ask}", err: "unknown type"},
+		{name: "invalid name", schema: "questions:\n  - {name: a-b, type: bool, message: Ask}", err: "name must be"},
+		{name: "missing message", schema: "questions:\n  - {name: A, type: bool}", err: "message is required"},
+		{name: "select without options", schema: "questions:\n  - {name: A, type: select, message: Ask}", err: "need options"},
+		{name: "default not an option", schema: "questions:\n  - {name: A, type: select, message: Ask, options: [x], default: y}", err: "invalid default"},
+		{name: "default does not match", schema: "questions:\n  - {name: A, type: string, message: Ask, validate: '^a$', default: b}", err: "invalid default"},
+		{name: "invalid pattern", schema: "questions:\n  - {name: A, type: string, message: Ask, validate: '('}", err: "invalid validate pattern"},
+		{name: "invalid when", schema: "questions:\n  - {name: A, type: bool, message: Ask, when: '{{'}", err: "invalid when condition"},
+		{name: "duplicate", schema: "questions:\n  - {name: A, type: bool, message: Ask}\n  - {name: A, type: bool, message: Ask}", err: "declared twice"},
+		{name: "unknown key", schema: "questions:\n  - {name: A, type: bool, message: Ask, depends: B}", err: "not found"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := questions.ParseSchema([]byte(tc.schema))
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("Expected error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestQuestionConditions(t *testing.T) {
+	schema, err := questions.ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+
+	enabled := func(name string, answers questions.Answers) bool {
+		for _, q := range schema.Questions {
+			if q.Name == name {
+				ok, err := q.Enabled(answers)
+				if err != nil {
+					t.Fatalf("Failed to evaluate %s: %v", name, err)
+				}
+				return ok
+			}
+		}
+		t.Fatalf("No question %s", name)
+		return false
+	}
+
+	if enabled("UseGateway", questions.Answers{"UseGRPC": false}) {
+		t.Error("UseGateway should be skipped without gRPC")
+	}
+	if !enabled("UseGateway", questions.Answers{"UseGRPC": true}) {
+		t.Error("UseGateway should be asked with gRPC")
+	}
+	if enabled("ZapSampling", questions.Answers{"Logger": "slog"}) {
+		t.Error("ZapSampling should be skipped for slog")
+	}
+	if !enabled("ZapSampling", questions.Answers{"Logger": "zap"}) {
+		t.Error("ZapSampling should be asked for zap")
+	}
+}
+
+func TestQuestionNormalize(t *testing.T) {
+	schema, err := questions.ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	byName := map[string]questions.Question{}
+	for _, q := range schema.Questions {
+		byName[q.Name] = q
+	}
+
+	testCases := []struct {
+		question string
+		value    interface{}
+		want     interface{}
+		valid    bool
+	}{
+		{question: "UseGRPC", value: true, want: true, valid: true},
+		{question: "UseGRPC", value: "yes", valid: false},
+		{question: "Port", value: float64(9090), want: 9090, valid: true},
+		{question: "Port", value: "9090", want: 9090, valid: true},
+		{question: "Port", value: 80.5, valid: false},
+		{question: "Owner", value: "payments", want: "payments", valid: true},
+		{question: "Owner", value: "Payments", valid: false},
+		{question: "Logger", value: "zap", want: "zap", valid: true},
+		{question: "Logger", value: "logrus", valid: false},
+		{question: "Middleware", value: []interface{}{"cors", "requestid"}, want: []string{"cors", "requestid"}, valid: true},
+		{question: "Middleware", value: []interface{}{"gzip"}, valid: false},
+		{question: "Middleware", value: "cors", valid: false},
+	}
+
+	for _, tc := range testCases {
+		q := byName[tc.question]
+		got, err := q.Normalize(tc.value)
+		if tc.valid {
+			if err != nil {
+				t.Errorf("%s(%v): unexpected error: %v", tc.question, tc.value, err)
+			} else if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("%s(%v): expected %v, got %v", tc.question, tc.value, tc.want, got)
+			}
+		} else if err == nil {
+			t.Errorf("%s(%v): expected an error", tc.question, tc.value)
+		}
+	}
+}
+
+func TestBuiltinQuestionSchemas(t *testing.T) {
+	for _, projectType := range project.ProjectTypes() {
+		qs, err := questions.ProjectQuestions(templates.TemplateFS, projectType)
+		if err != nil {
+			t.Errorf("%s: %v", projectType, err)
+			continue
+		}
+		if len(qs) == 0 {
+			t.Errorf("%s: template declares no questions", projectType)
+		}
+	}
+
+	answers, err := questions.ResolveAnswers(templates.TemplateFS, "api", map[string]interface{}{"UseRedis": true})
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	want := questions.Answers{"Logger": "slog", "UsePostgres": true, "UseRedis": true, "UseRabbitMQ": false, "UseMetrics": false, "UseTracing": false, "Auth": "none", "Middleware": []string{"requestid", "recovery", "cors", "bodylimit", "ratelimit", "gzip", "secure"}, "DI": "manual"}
+	if !reflect.DeepEqual(answers.Values, want) {
+		t.Errorf("Expected answers %v, got %v", want, answers.Values)
+	}
+}
+
+func TestQuestionParseString(t *testing.T) {
+	schema, err := questions.ParseSchema([]byte(testSchema))
+	if err != nil {
+		t.Fatalf("Failed to parse schema: %v", err)
+	}
+	byName := map[string]questions.Question{}
+	for _, q := range schema.Questions {
+		byName[q.Name] = q
+	}
+
+	testCases := []struct {
+		question string
+		value    string
+		want     interface{}
+		valid    bool
+	}{
+		{question: "UseGRPC", value: "true", want: true, valid: true},
+		{question: "UseGRPC", value: "maybe", valid: false},
+		{question: "Port", value: "9090", want: 9090, valid: true},
+		{question: "Logger", value: "zap", want: "zap", valid: true},
+		{question: "Middleware", value: "cors, requestid", want: []string{"cors", "requestid"}, valid: true},
+		{question: "Middleware", value: "", want: []string{}, valid: true},
+		{question: "Middleware", value: "cors,gzip", valid: false},
+	}
+
+	for _, tc := range testCases {
+		q := byName[tc.question]
+		got, err := q.ParseString(tc.value)
+		if tc.valid {
+			if err != nil {
+				t.Errorf("%s(%q): unexpected error: %v", tc.question, tc.value, err)
+			} else if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("%s(%q): expected %v, got %v", tc.question, tc.value, tc.want, got)
+			}
+		} else if err == nil {
+			t.Errorf("%s(%q): expected an error", tc.question, tc.value)
+		}
+	}
+}
+
+func TestTemplateDirectoryQuestions(t *testing.T) {
+	builtin, err := fs.ReadFile(templates.TemplateFS, "api/questions.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read questions: %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "api"), 0755); err != nil {
+		t.Fatalf("Failed to create template directory: %v", err)
+	}
+	custom := string(builtin) + `  - name: UseKafka
+    type: bool
+    message: Would you like to use Kafka?
+    default: false
+`
+	if err := os.WriteFile(filepath.Join(dir, "api", "questions.yaml"), []byte(custom), 0644); err != nil {
+		t.Fatalf("Failed to write questions: %v", err)
+	}
+
+	cfg := testConfig()
+	cfg.Templates.Directory = dir
+	fsys := project.TemplateFS(cfg)
+
+	qs, err := questions.ProjectQuestions(fsys, "api")
+	if err != nil {
+		t.Fatalf("Failed to load questions: %v", err)
+	}
+	if last := qs[len(qs)-1]; last.Name != "UseKafka" {
+		t.Errorf("Expected the question of the template directory, got %s", last.Name)
+	}
+
+	answers, err := questions.ResolveAnswers(fsys, "api", map[string]interface{}{"UseKafka": true})
+	if err != nil {
+		t.Fatalf("Failed to resolve answers: %v", err)
+	}
+	if !answers.Values.Bool("UseKafka") {
+		t.Errorf("Expected UseKafka to be answered, got %v", answers.Values)
+	}
+
+	schema, err := questions.JSONSchema(fsys, "api")
+	if err != nil {
+		t.Fatalf("Failed to build schema: %v", err)
+	}
+	if _, ok := schema["properties"].(map[string]interface{})["UseKafka"]; !ok {
+		t.Error("Expected UseKafka in the JSON schema")
+	}
+
+	if _, err := questions.ResolveAnswers(templates.TemplateFS, "api", map[string]interface{}{"UseKafka": true}); err == nil {
+		t.Error("Expected the built-in template to reject UseKafka")
+	}
+
+	configFile := filepath.Join(dir, "sova.yaml")
+	if err := os.WriteFile(configFile, []byte("templates:\n  directory: "+dir+"\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	if output, err := runSova(t, dir, "presets", "save", "kafka", "--template", "api", "--set", "UseKafka=true", "--config", configFile); err != nil {
+		t.Errorf("Failed to save preset with a custom question: %v\n%s", err, output)
+	}
+}
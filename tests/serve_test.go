@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/go-sova/sova-cli/internal/events"
+	"github.com/go-sova/sova-cli/internal/project"
+	"github.com/go-sova/sova-cli/internal/serve"
+	"github.com/go-sova/sova-cli/pkg/questions"
+	"github.com/go-sova/sova-cli/pkg/utils"
+	"github.com/go-sova/sova-cli/pkg/vfs"
+	"github.com/go-sova/sova-cli/templates"
+)
+
+func newServeServer(t *testing.T, opts serve.Options) *httptest.Server {
+	t.Helper()
+
+	logger := utils.NewLogger(utils.Error)
+	logger.SetOutput(io.Discard)
+	opts.Logger = logger
+
+	server := httptest.NewServer(serve.NewHandler(opts))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func postGenerate(t *testing.T, server *httptest.Server, projectType, body string) (*http.Response, []byte) {
+	t.Helper()
+
+	resp, err := http.Post(server.URL+"/v1/templates/"+projectType+"/generate", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return resp, data
+}
+
+func TestServeTemplates(t *testing.T) {
+	server := newServeServer(t, serve.Options{})
+
+	resp, err := http.Get(server.URL + "/v1/templates")
+	if err != nil {
+		t.Fatalf("Failed to list templates: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+
+	var list struct {
+		Templates []serve.TemplateInfo `json:"templates"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatalf("Failed to decode templates: %v", err)
+	}
+
+	schemas := map[string]map[string]interface{}{}
+	for _, info := range list.Templates {
+		schemas[info.Name] = info.Schema
+	}
+
+	for _, projectType := range project.ProjectTypes() {
+		schema, ok := schemas[projectType]
+		if !ok {
+			t.Errorf("Template %s is not listed", projectType)
+			continue
+		}
+		qs, err := questions.ProjectQuestions(templates.TemplateFS, projectType)
+		if err != nil {
+			t.Fatalf("Failed to load questions: %v", err)
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, q := range qs {
+			if _, ok := properties[q.Name]; !ok {
+				t.Errorf("%s: schema does not describe %s", projectType, q.Name)
+			}
+		}
+	}
+}
+
+func TestServeGenerate(t *testing.T) {
+	server := newServeServer(t, serve.Options{})
+
+	testCases := []struct {
+		name   string
+		body   string
+		read   func(*testing.T, []byte) []archiveEntry
+		expect string
+	}{
+		{
+			name:   "tar.gz",
+			body:   `{"project_name": "svc", "answers": {"UseRedis": true}}`,
+			read:   readTarGz,
+			expect: "svc/internal/platform/redis.go",
+		},
+		{
+			name:   "zip",
+			body:   `{"project_name": "svc", "format": "zip", "answers": {"UsePostgres": false}}`,
+			read:   readZip,
+			expect: "svc/go.mod",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, data := postGenerate(t, server, "api", tc.body)
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", resp.StatusCode, data)
+			}
+
+			present := map[string]bool{}
+			for _, entry := range tc.read(t, data) {
+				present[entry.name] = true
+			}
+			if !present[tc.expect] {
+				t.Errorf("Expected entry %s in archive", tc.expect)
+			}
+		})
+	}
+}
+
+func TestServeRejectsInvalidRequests(t *testing.T) {
+	server := newServeServer(t, serve.Options{MaxRequestBytes: 256})
+
+	testCases := []struct {
+		name        string
+		projectType string
+		body        string
+		status      int
+	}{
+		{name: "invalid project name", projectType: "api", body: `{"project_name": "../etc"}`, status: http.StatusBadRequest},
+		{name: "missing project name", projectType: "api", body: `{}`, status: http.StatusBadRequest},
+		{name: "unknown answer", projectType: "cli", body: `{"project_name": "tool", "answers": {"UseRedis": true}}`, status: http.StatusBadRequest},
+		{name: "non-boolean answer", projectType: "api", body: `{"project_name": "svc", "answers": {"UseRedis": "yes"}}`, status: http.StatusBadRequest},
+		{name: "unknown field", projectType: "api", body: `{"project_name": "svc", "extra": 1}`, status: http.StatusBadRequest},
+		{name: "unknown format", projectType: "api", body: `{"project_name": "svc", "format": "rar"}`, status: http.StatusBadRequest},
+		{name: "unknown template", projectType: "worker", body: `{"project_name": "svc"}`, status: http.StatusNotFound},
+		{name: "oversized body", projectType: "api", body: `{"project_name": "` + strings.Repeat("a", 512) + `"}`, status: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, data := postGenerate(t, server, tc.projectType, tc.body)
+			if resp.StatusCode != tc.status {
+				t.Errorf("Expected status %d, got %d: %s", tc.status, resp.StatusCode, data)
+			}
+		})
+	}
+
+	t.Run("wrong method", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/v1/templates/api/generate")
+		if err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			t.Errorf("Expected status 405, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestServeProjectSizeLimit(t *testing.T) {
+	server := newServeServer(t, serve.Options{MaxProjectBytes: 1024})
+
+	resp, data := postGenerate(t, server, "api", `{"project_name": "svc"}`)
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status 413, got %d: %s", resp.StatusCode, data)
+	}
+}
+
+func TestServeConcurrentRequests(t *testing.T) {
+	server := newServeServer(t, serve.Options{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp, err := http.Post(server.URL+"/v1/templates/api/generate", "application/json", strings.NewReader(`{"project_name": "svc"}`))
+			if err != nil {
+				t.Errorf("Failed to send request: %v", err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("Expected status 200, got %d", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestGenerationStopsWithContext(t *testing.T) {
+	for _, projectType := range project.ProjectTypes() {
+		t.Run(projectType, func(t *testing.T) {
+			answers, err := questions.ResolveAnswers(templates.TemplateFS, projectType, nil)
+			if err != nil {
+				t.Fatalf("Failed to resolve answers: %v", err)
+			}
+			gen, err := project.NewGenerator(projectType, "svc", "svc", answers)
+			if err != nil {
+				t.Fatalf("Failed to create generator: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			out := vfs.NewMemFS()
+			gen.SetFS(out)
+			gen.SetEvents(events.Discard)
+			gen.SetContext(ctx)
+
+			if err := project.Run(gen); !errors.Is(err, context.Canceled) {
+				t.Errorf("Expected generation to stop with context.Canceled, got %v", err)
+			}
+			if out.Size() != 0 {
+				t.Errorf("Expected no files to be written, got %d bytes", out.Size())
+			}
+		})
+	}
+}
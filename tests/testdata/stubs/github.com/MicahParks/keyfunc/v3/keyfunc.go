@@ -0,0 +1,15 @@
+// Package keyfunc is a type-only stub of github.com/MicahParks/keyfunc/v3.
+package keyfunc
+
+import (
+	"context"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Keyfunc interface {
+	Keyfunc(token *jwt.Token) (any, error)
+}
+
+func NewDefault(urls []string) (Keyfunc, error)                         { panic("stub") }
+func NewDefaultCtx(ctx context.Context, urls []string) (Keyfunc, error) { panic("stub") }
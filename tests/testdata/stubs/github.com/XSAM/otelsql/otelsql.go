@@ -0,0 +1,15 @@
+// Package otelsql is a type-only stub of github.com/XSAM/otelsql.
+package otelsql
+
+import (
+	"database/sql"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type Option interface{ apply() }
+
+func Open(driverName, dataSourceName string, options ...Option) (*sql.DB, error) {
+	panic("stub")
+}
+func WithAttributes(attributes ...attribute.KeyValue) Option { panic("stub") }
@@ -0,0 +1,9 @@
+// Package binding is a type-only stub of github.com/gin-gonic/gin/binding.
+package binding
+
+type StructValidator interface {
+	ValidateStruct(any) error
+	Engine() any
+}
+
+var Validator StructValidator
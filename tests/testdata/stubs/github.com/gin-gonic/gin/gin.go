@@ -0,0 +1,128 @@
+// Package gin is a type-only stub of github.com/gin-gonic/gin used to
+// type-check generated projects without downloading dependencies.
+package gin
+
+import "net/http"
+
+type H map[string]any
+
+type HandlerFunc func(*Context)
+
+type HandlersChain []HandlerFunc
+
+type ResponseWriter interface {
+	http.ResponseWriter
+	Status() int
+	Size() int
+	Written() bool
+	WriteString(s string) (int, error)
+	Flush()
+}
+
+type Context struct {
+	Request *http.Request
+	Writer  ResponseWriter
+	Keys    map[string]any
+	Errors  errorMsgs
+}
+
+func (c *Context) Next()                                        { panic("stub") }
+func (c *Context) Abort()                                       { panic("stub") }
+func (c *Context) JSON(code int, obj any)                       { panic("stub") }
+func (c *Context) Status(code int)                              { panic("stub") }
+func (c *Context) Set(key string, value any)                    { panic("stub") }
+func (c *Context) Get(key string) (any, bool)                   { panic("stub") }
+func (c *Context) GetHeader(key string) string                  { panic("stub") }
+func (c *Context) Header(key, value string)                     { panic("stub") }
+func (c *Context) Param(key string) string                      { panic("stub") }
+func (c *Context) Query(key string) string                      { panic("stub") }
+func (c *Context) DefaultQuery(key, defaultValue string) string { panic("stub") }
+func (c *Context) GetQuery(key string) (string, bool)           { panic("stub") }
+func (c *Context) QueryArray(key string) []string               { panic("stub") }
+func (c *Context) FullPath() string                             { panic("stub") }
+func (c *Context) ClientIP() string                             { panic("stub") }
+func (c *Context) ShouldBindJSON(obj any) error                 { panic("stub") }
+func (c *Context) AbortWithStatus(code int)                     { panic("stub") }
+func (c *Context) Error(err error) *Error                       { panic("stub") }
+func (c *Context) Data(code int, contentType string, data []byte) {
+	panic("stub")
+}
+func (c *Context) AbortWithStatusJSON(code int, jsonObj any) {
+	panic("stub")
+}
+
+type Error struct {
+	Err  error
+	Type uint64
+	Meta any
+}
+
+func (msg *Error) Error() string { panic("stub") }
+
+type errorMsgs []*Error
+
+func (a errorMsgs) Last() *Error { panic("stub") }
+
+type IRoutes interface {
+	Use(...HandlerFunc) IRoutes
+	Handle(string, string, ...HandlerFunc) IRoutes
+	GET(string, ...HandlerFunc) IRoutes
+	POST(string, ...HandlerFunc) IRoutes
+	PUT(string, ...HandlerFunc) IRoutes
+	PATCH(string, ...HandlerFunc) IRoutes
+	DELETE(string, ...HandlerFunc) IRoutes
+}
+
+type RouterGroup struct {
+	Handlers HandlersChain
+}
+
+func (group *RouterGroup) Use(middleware ...HandlerFunc) IRoutes { panic("stub") }
+func (group *RouterGroup) Group(relativePath string, handlers ...HandlerFunc) *RouterGroup {
+	panic("stub")
+}
+func (group *RouterGroup) Handle(httpMethod, relativePath string, handlers ...HandlerFunc) IRoutes {
+	panic("stub")
+}
+func (group *RouterGroup) Any(relativePath string, handlers ...HandlerFunc) IRoutes {
+	panic("stub")
+}
+func (group *RouterGroup) GET(relativePath string, handlers ...HandlerFunc) IRoutes {
+	panic("stub")
+}
+func (group *RouterGroup) POST(relativePath string, handlers ...HandlerFunc) IRoutes {
+	panic("stub")
+}
+func (group *RouterGroup) PUT(relativePath string, handlers ...HandlerFunc) IRoutes {
+	panic("stub")
+}
+func (group *RouterGroup) PATCH(relativePath string, handlers ...HandlerFunc) IRoutes {
+	panic("stub")
+}
+func (group *RouterGroup) DELETE(relativePath string, handlers ...HandlerFunc) IRoutes {
+	panic("stub")
+}
+
+type Engine struct {
+	RouterGroup
+}
+
+func New() *Engine     { panic("stub") }
+func Default() *Engine { panic("stub") }
+
+func Recovery() HandlerFunc { panic("stub") }
+
+func WrapH(h http.Handler) HandlerFunc { panic("stub") }
+
+func (engine *Engine) Use(middleware ...HandlerFunc) IRoutes              { panic("stub") }
+func (engine *Engine) NoRoute(handlers ...HandlerFunc)                    { panic("stub") }
+func (engine *Engine) Run(addr ...string) (err error)                     { panic("stub") }
+func (engine *Engine) ServeHTTP(w http.ResponseWriter, req *http.Request) { panic("stub") }
+
+const (
+	DebugMode   = "debug"
+	ReleaseMode = "release"
+	TestMode    = "test"
+)
+
+func SetMode(value string) { panic("stub") }
@@ -0,0 +1,31 @@
+// Package validator is a type-only stub of
+// github.com/go-playground/validator/v10.
+package validator
+
+import "reflect"
+
+type TagNameFunc func(field reflect.StructField) string
+
+type Validate struct{}
+
+func New() *Validate                                   { panic("stub") }
+func (v *Validate) RegisterTagNameFunc(fn TagNameFunc) { panic("stub") }
+func (v *Validate) Struct(s interface{}) error         { panic("stub") }
+
+type FieldError interface {
+	Tag() string
+	ActualTag() string
+	Namespace() string
+	StructNamespace() string
+	Field() string
+	StructField() string
+	Value() interface{}
+	Param() string
+	Kind() reflect.Kind
+	Type() reflect.Type
+	Error() string
+}
+
+type ValidationErrors []FieldError
+
+func (ve ValidationErrors) Error() string { panic("stub") }
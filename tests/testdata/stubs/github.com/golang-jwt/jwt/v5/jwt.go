@@ -0,0 +1,66 @@
+// Package jwt is a type-only stub of github.com/golang-jwt/jwt/v5.
+package jwt
+
+import "time"
+
+type ClaimStrings []string
+
+type NumericDate struct {
+	time.Time
+}
+
+type Claims interface {
+	GetExpirationTime() (*NumericDate, error)
+	GetIssuedAt() (*NumericDate, error)
+	GetNotBefore() (*NumericDate, error)
+	GetIssuer() (string, error)
+	GetSubject() (string, error)
+	GetAudience() (ClaimStrings, error)
+}
+
+type RegisteredClaims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  ClaimStrings `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+func (c RegisteredClaims) GetExpirationTime() (*NumericDate, error) { panic("stub") }
+func (c RegisteredClaims) GetIssuedAt() (*NumericDate, error)       { panic("stub") }
+func (c RegisteredClaims) GetNotBefore() (*NumericDate, error)      { panic("stub") }
+func (c RegisteredClaims) GetIssuer() (string, error)               { panic("stub") }
+func (c RegisteredClaims) GetSubject() (string, error)              { panic("stub") }
+func (c RegisteredClaims) GetAudience() (ClaimStrings, error)       { panic("stub") }
+
+type MapClaims map[string]interface{}
+
+type Token struct {
+	Raw    string
+	Header map[string]interface{}
+	Claims Claims
+	Valid  bool
+}
+
+type Keyfunc func(*Token) (interface{}, error)
+
+type ParserOption func(*Parser)
+
+type Parser struct{}
+
+func NewParser(options ...ParserOption) *Parser { panic("stub") }
+func (p *Parser) Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
+	panic("stub")
+}
+func (p *Parser) ParseWithClaims(tokenString string, claims Claims, keyFunc Keyfunc) (*Token, error) {
+	panic("stub")
+}
+
+func WithValidMethods(methods []string) ParserOption { panic("stub") }
+func WithIssuer(iss string) ParserOption             { panic("stub") }
+func WithAudience(aud string) ParserOption           { panic("stub") }
+func WithSubject(sub string) ParserOption            { panic("stub") }
+func WithExpirationRequired() ParserOption           { panic("stub") }
+func WithLeeway(leeway time.Duration) ParserOption   { panic("stub") }
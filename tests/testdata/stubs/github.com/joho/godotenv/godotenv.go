@@ -0,0 +1,4 @@
+// Package godotenv is a type-only stub of github.com/joho/godotenv.
+package godotenv
+
+func Load(filenames ...string) (err error) { panic("stub") }
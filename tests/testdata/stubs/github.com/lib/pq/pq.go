@@ -0,0 +1,3 @@
+// Package pq is a type-only stub of github.com/lib/pq. Generated projects
+// only import it for its driver registration side effect.
+package pq
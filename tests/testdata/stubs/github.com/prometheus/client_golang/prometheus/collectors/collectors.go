@@ -0,0 +1,17 @@
+// Package collectors is a type-only stub of
+// github.com/prometheus/client_golang/prometheus/collectors.
+package collectors
+
+import "github.com/prometheus/client_golang/prometheus"
+
+type GoCollectorOption func(*struct{})
+
+func NewGoCollector(opts ...GoCollectorOption) prometheus.Collector { panic("stub") }
+
+type ProcessCollectorOpts struct {
+	PidFn        func() (int, error)
+	Namespace    string
+	ReportErrors bool
+}
+
+func NewProcessCollector(opts ProcessCollectorOpts) prometheus.Collector { panic("stub") }
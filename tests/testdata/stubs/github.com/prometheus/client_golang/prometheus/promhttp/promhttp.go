@@ -0,0 +1,17 @@
+// Package promhttp is a type-only stub of
+// github.com/prometheus/client_golang/prometheus/promhttp.
+package promhttp
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type HandlerOpts struct {
+	Registry          prometheus.Registerer
+	EnableOpenMetrics bool
+}
+
+func Handler() http.Handler                                             { panic("stub") }
+func HandlerFor(reg prometheus.Gatherer, opts HandlerOpts) http.Handler { panic("stub") }
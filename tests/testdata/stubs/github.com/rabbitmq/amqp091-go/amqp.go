@@ -0,0 +1,50 @@
+// Package amqp091 is a type-only stub of github.com/rabbitmq/amqp091-go.
+package amqp091
+
+import (
+	"context"
+	"time"
+)
+
+type Table map[string]interface{}
+
+type Publishing struct {
+	Headers       Table
+	ContentType   string
+	DeliveryMode  uint8
+	CorrelationId string
+	MessageId     string
+	Timestamp     time.Time
+	Body          []byte
+}
+
+type Delivery struct {
+	Headers     Table
+	ContentType string
+	MessageId   string
+	Redelivered bool
+	Exchange    string
+	RoutingKey  string
+	Body        []byte
+}
+
+func (d Delivery) Ack(multiple bool) error           { panic("stub") }
+func (d Delivery) Nack(multiple, requeue bool) error { panic("stub") }
+func (d Delivery) Reject(requeue bool) error         { panic("stub") }
+
+type Channel struct{}
+
+func (ch *Channel) Close() error { panic("stub") }
+func (ch *Channel) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg Publishing) error {
+	panic("stub")
+}
+func (ch *Channel) Consume(queue, consumer string, autoAck, exclusive, noLocal, noWait bool, args Table) (<-chan Delivery, error) {
+	panic("stub")
+}
+
+type Connection struct{}
+
+func Dial(url string) (*Connection, error)       { panic("stub") }
+func (c *Connection) Channel() (*Channel, error) { panic("stub") }
+func (c *Connection) IsClosed() bool             { panic("stub") }
+func (c *Connection) Close() error               { panic("stub") }
@@ -0,0 +1,9 @@
+// Package redisotel is a type-only stub of
+// github.com/redis/go-redis/extra/redisotel/v9.
+package redisotel
+
+import "github.com/redis/go-redis/v9"
+
+type TracingOption interface{ apply() }
+
+func InstrumentTracing(rdb redis.UniversalClient, opts ...TracingOption) error { panic("stub") }
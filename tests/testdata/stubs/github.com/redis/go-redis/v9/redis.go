@@ -0,0 +1,47 @@
+// Package redis is a type-only stub of github.com/redis/go-redis/v9.
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+type Options struct {
+	Addr     string
+	Password string
+	DB       int
+}
+
+func ParseURL(redisURL string) (*Options, error) { panic("stub") }
+
+type StatusCmd struct{}
+
+func (cmd *StatusCmd) Err() error              { panic("stub") }
+func (cmd *StatusCmd) Result() (string, error) { panic("stub") }
+
+type IntCmd struct{}
+
+func (cmd *IntCmd) Err() error             { panic("stub") }
+func (cmd *IntCmd) Result() (int64, error) { panic("stub") }
+
+type BoolCmd struct{}
+
+func (cmd *BoolCmd) Err() error            { panic("stub") }
+func (cmd *BoolCmd) Result() (bool, error) { panic("stub") }
+
+type Client struct{}
+
+func NewClient(opt *Options) *Client                  { panic("stub") }
+func (c *Client) Ping(ctx context.Context) *StatusCmd { panic("stub") }
+func (c *Client) Close() error                        { panic("stub") }
+func (c *Client) Incr(ctx context.Context, key string) *IntCmd {
+	panic("stub")
+}
+func (c *Client) Expire(ctx context.Context, key string, expiration time.Duration) *BoolCmd {
+	panic("stub")
+}
+
+type UniversalClient interface {
+	Ping(ctx context.Context) *StatusCmd
+	Close() error
+}
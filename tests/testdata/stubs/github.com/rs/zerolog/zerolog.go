@@ -0,0 +1,54 @@
+// Package zerolog is a type-only stub of github.com/rs/zerolog.
+package zerolog
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+type Level int8
+
+func ParseLevel(levelStr string) (Level, error) { panic("stub") }
+
+type Logger struct{}
+
+func New(w io.Writer) Logger          { panic("stub") }
+func Nop() Logger                     { panic("stub") }
+func Ctx(ctx context.Context) *Logger { panic("stub") }
+
+func (l Logger) Level(lvl Level) Logger                          { panic("stub") }
+func (l Logger) With() Context                                   { panic("stub") }
+func (l Logger) WithContext(ctx context.Context) context.Context { panic("stub") }
+func (l *Logger) Debug() *Event                                  { panic("stub") }
+func (l *Logger) Info() *Event                                   { panic("stub") }
+func (l *Logger) Warn() *Event                                   { panic("stub") }
+func (l *Logger) Error() *Event                                  { panic("stub") }
+func (l *Logger) Fatal() *Event                                  { panic("stub") }
+
+type Context struct{}
+
+func (c Context) Str(key, val string) Context { panic("stub") }
+func (c Context) Timestamp() Context          { panic("stub") }
+func (c Context) Logger() Logger              { panic("stub") }
+
+type Event struct{}
+
+func (e *Event) Str(key, val string) *Event             { panic("stub") }
+func (e *Event) Int(key string, i int) *Event           { panic("stub") }
+func (e *Event) Bool(key string, b bool) *Event         { panic("stub") }
+func (e *Event) Dur(key string, d time.Duration) *Event { panic("stub") }
+func (e *Event) Err(err error) *Event                   { panic("stub") }
+func (e *Event) Bytes(key string, val []byte) *Event    { panic("stub") }
+func (e *Event) Interface(key string, i interface{}) *Event {
+	panic("stub")
+}
+func (e *Event) Msg(msg string)                       { panic("stub") }
+func (e *Event) Msgf(format string, v ...interface{}) { panic("stub") }
+
+type ConsoleWriter struct {
+	Out     io.Writer
+	NoColor bool
+}
+
+func (w ConsoleWriter) Write(p []byte) (n int, err error) { panic("stub") }
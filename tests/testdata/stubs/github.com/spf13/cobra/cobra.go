@@ -0,0 +1,51 @@
+// Package cobra is a type-only stub of github.com/spf13/cobra.
+package cobra
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/pflag"
+)
+
+type PositionalArgs func(cmd *Command, args []string) error
+
+type Command struct {
+	Use               string
+	Aliases           []string
+	Short             string
+	Long              string
+	Example           string
+	Args              PositionalArgs
+	SilenceUsage      bool
+	SilenceErrors     bool
+	PersistentPreRun  func(cmd *Command, args []string)
+	PersistentPreRunE func(cmd *Command, args []string) error
+	PersistentPostRun func(cmd *Command, args []string)
+	PreRunE           func(cmd *Command, args []string) error
+	Run               func(cmd *Command, args []string)
+	RunE              func(cmd *Command, args []string) error
+}
+
+func (c *Command) Execute() error                           { panic("stub") }
+func (c *Command) ExecuteContext(ctx context.Context) error { panic("stub") }
+func (c *Command) AddCommand(cmds ...*Command)              { panic("stub") }
+func (c *Command) Flags() *pflag.FlagSet                    { panic("stub") }
+func (c *Command) PersistentFlags() *pflag.FlagSet          { panic("stub") }
+func (c *Command) Context() context.Context                 { panic("stub") }
+func (c *Command) SetContext(ctx context.Context)           { panic("stub") }
+func (c *Command) OutOrStdout() io.Writer                   { panic("stub") }
+func (c *Command) ErrOrStderr() io.Writer                   { panic("stub") }
+func (c *Command) Help() error                              { panic("stub") }
+func (c *Command) MarkFlagRequired(name string) error       { panic("stub") }
+func (c *Command) Printf(format string, i ...interface{})   { panic("stub") }
+func (c *Command) Println(i ...interface{})                 { panic("stub") }
+func (c *Command) Name() string                             { panic("stub") }
+func (c *Command) Root() *Command                           { panic("stub") }
+
+func OnInitialize(y ...func())                 { panic("stub") }
+func CheckErr(msg interface{})                 { panic("stub") }
+func NoArgs(cmd *Command, args []string) error { panic("stub") }
+func ExactArgs(n int) PositionalArgs           { panic("stub") }
+func MaximumNArgs(n int) PositionalArgs        { panic("stub") }
+func MinimumNArgs(n int) PositionalArgs        { panic("stub") }
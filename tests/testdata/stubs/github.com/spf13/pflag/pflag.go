@@ -0,0 +1,26 @@
+// Package pflag is a type-only stub of github.com/spf13/pflag.
+package pflag
+
+type FlagSet struct{}
+
+func (f *FlagSet) String(name string, value string, usage string) *string { panic("stub") }
+func (f *FlagSet) StringP(name, shorthand string, value string, usage string) *string {
+	panic("stub")
+}
+func (f *FlagSet) StringVar(p *string, name string, value string, usage string) { panic("stub") }
+func (f *FlagSet) StringVarP(p *string, name, shorthand string, value string, usage string) {
+	panic("stub")
+}
+func (f *FlagSet) Bool(name string, value bool, usage string) *bool { panic("stub") }
+func (f *FlagSet) BoolP(name, shorthand string, value bool, usage string) *bool {
+	panic("stub")
+}
+func (f *FlagSet) BoolVar(p *bool, name string, value bool, usage string) { panic("stub") }
+func (f *FlagSet) BoolVarP(p *bool, name, shorthand string, value bool, usage string) {
+	panic("stub")
+}
+func (f *FlagSet) Int(name string, value int, usage string) *int       { panic("stub") }
+func (f *FlagSet) IntVar(p *int, name string, value int, usage string) { panic("stub") }
+func (f *FlagSet) GetString(name string) (string, error)               { panic("stub") }
+func (f *FlagSet) GetBool(name string) (bool, error)                   { panic("stub") }
+func (f *FlagSet) GetInt(name string) (int, error)                     { panic("stub") }
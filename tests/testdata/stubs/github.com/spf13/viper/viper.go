@@ -0,0 +1,25 @@
+// Package viper is a type-only stub of github.com/spf13/viper.
+package viper
+
+type ConfigFileNotFoundError struct{}
+
+func (e ConfigFileNotFoundError) Error() string { panic("stub") }
+
+type DecoderConfigOption func(interface{})
+
+func SetConfigFile(in string)                                         { panic("stub") }
+func SetConfigName(in string)                                         { panic("stub") }
+func SetConfigType(in string)                                         { panic("stub") }
+func AddConfigPath(in string)                                         { panic("stub") }
+func SetEnvPrefix(in string)                                          { panic("stub") }
+func AutomaticEnv()                                                   { panic("stub") }
+func ReadInConfig() error                                             { panic("stub") }
+func ConfigFileUsed() string                                          { panic("stub") }
+func SetDefault(key string, value interface{})                        { panic("stub") }
+func Set(key string, value interface{})                               { panic("stub") }
+func Get(key string) interface{}                                      { panic("stub") }
+func GetString(key string) string                                     { panic("stub") }
+func GetBool(key string) bool                                         { panic("stub") }
+func GetInt(key string) int                                           { panic("stub") }
+func Unmarshal(rawVal interface{}, opts ...DecoderConfigOption) error { panic("stub") }
+func WriteConfig() error                                              { panic("stub") }
@@ -0,0 +1,9 @@
+// Package otelgin is a type-only stub of
+// go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin.
+package otelgin
+
+import "github.com/gin-gonic/gin"
+
+type Option interface{ apply() }
+
+func Middleware(service string, opts ...Option) gin.HandlerFunc { panic("stub") }
@@ -0,0 +1,17 @@
+// Package attribute is a type-only stub of go.opentelemetry.io/otel/attribute.
+package attribute
+
+type Key string
+
+type Value struct{}
+
+type KeyValue struct {
+	Key   Key
+	Value Value
+}
+
+func String(k, v string) KeyValue          { panic("stub") }
+func Int(k string, v int) KeyValue         { panic("stub") }
+func Int64(k string, v int64) KeyValue     { panic("stub") }
+func Bool(k string, v bool) KeyValue       { panic("stub") }
+func Float64(k string, v float64) KeyValue { panic("stub") }
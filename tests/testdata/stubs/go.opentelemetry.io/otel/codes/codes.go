@@ -0,0 +1,10 @@
+// Package codes is a type-only stub of go.opentelemetry.io/otel/codes.
+package codes
+
+type Code uint32
+
+const (
+	Unset Code = 0
+	Error Code = 1
+	Ok    Code = 2
+)
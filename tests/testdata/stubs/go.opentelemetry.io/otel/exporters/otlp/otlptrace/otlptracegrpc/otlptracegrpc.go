@@ -0,0 +1,23 @@
+// Package otlptracegrpc is a type-only stub of
+// go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc.
+package otlptracegrpc
+
+import (
+	"context"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type Option interface{ apply() }
+
+// Exporter stands in for otlptrace.Exporter.
+type Exporter struct{}
+
+func (e *Exporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	panic("stub")
+}
+func (e *Exporter) Shutdown(ctx context.Context) error { panic("stub") }
+
+func New(ctx context.Context, opts ...Option) (*Exporter, error) { panic("stub") }
+func WithInsecure() Option                                       { panic("stub") }
+func WithEndpoint(endpoint string) Option                        { panic("stub") }
@@ -0,0 +1,13 @@
+// Package otel is a type-only stub of go.opentelemetry.io/otel.
+package otel
+
+import (
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Tracer(name string, opts ...trace.TracerOption) trace.Tracer   { panic("stub") }
+func GetTracerProvider() trace.TracerProvider                       { panic("stub") }
+func SetTracerProvider(tp trace.TracerProvider)                     { panic("stub") }
+func GetTextMapPropagator() propagation.TextMapPropagator           { panic("stub") }
+func SetTextMapPropagator(propagator propagation.TextMapPropagator) { panic("stub") }
@@ -0,0 +1,39 @@
+// Package propagation is a type-only stub of
+// go.opentelemetry.io/otel/propagation.
+package propagation
+
+import "context"
+
+type TextMapCarrier interface {
+	Get(key string) string
+	Set(key string, value string)
+	Keys() []string
+}
+
+type TextMapPropagator interface {
+	Inject(ctx context.Context, carrier TextMapCarrier)
+	Extract(ctx context.Context, carrier TextMapCarrier) context.Context
+	Fields() []string
+}
+
+type HeaderCarrier map[string][]string
+
+func (hc HeaderCarrier) Get(key string) string        { panic("stub") }
+func (hc HeaderCarrier) Set(key string, value string) { panic("stub") }
+func (hc HeaderCarrier) Keys() []string               { panic("stub") }
+
+type TraceContext struct{}
+
+func (TraceContext) Inject(ctx context.Context, carrier TextMapCarrier) { panic("stub") }
+func (TraceContext) Extract(ctx context.Context, carrier TextMapCarrier) context.Context {
+	panic("stub")
+}
+func (TraceContext) Fields() []string { panic("stub") }
+
+type Baggage struct{}
+
+func (Baggage) Inject(ctx context.Context, carrier TextMapCarrier)                  { panic("stub") }
+func (Baggage) Extract(ctx context.Context, carrier TextMapCarrier) context.Context { panic("stub") }
+func (Baggage) Fields() []string                                                    { panic("stub") }
+
+func NewCompositeTextMapPropagator(p ...TextMapPropagator) TextMapPropagator { panic("stub") }
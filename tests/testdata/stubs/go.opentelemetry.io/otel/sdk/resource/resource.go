@@ -0,0 +1,20 @@
+// Package resource is a type-only stub of go.opentelemetry.io/otel/sdk/resource.
+package resource
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type Resource struct{}
+
+type Option interface{ apply() }
+
+func New(ctx context.Context, opts ...Option) (*Resource, error) { panic("stub") }
+func Default() *Resource                                         { panic("stub") }
+func WithAttributes(attributes ...attribute.KeyValue) Option     { panic("stub") }
+func WithFromEnv() Option                                        { panic("stub") }
+func WithTelemetrySDK() Option                                   { panic("stub") }
+func WithHost() Option                                           { panic("stub") }
+func WithProcess() Option                                        { panic("stub") }
@@ -0,0 +1,33 @@
+// Package trace is a type-only stub of go.opentelemetry.io/otel/sdk/trace.
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type ReadOnlySpan interface{}
+
+type SpanExporter interface {
+	ExportSpans(ctx context.Context, spans []ReadOnlySpan) error
+	Shutdown(ctx context.Context) error
+}
+
+type TracerProviderOption interface{ apply() }
+type BatchSpanProcessorOption func(*struct{})
+
+func WithBatcher(e SpanExporter, opts ...BatchSpanProcessorOption) TracerProviderOption {
+	panic("stub")
+}
+func WithResource(r *resource.Resource) TracerProviderOption { panic("stub") }
+
+type TracerProvider struct{}
+
+func NewTracerProvider(opts ...TracerProviderOption) *TracerProvider { panic("stub") }
+func (p *TracerProvider) Tracer(name string, options ...trace.TracerOption) trace.Tracer {
+	panic("stub")
+}
+func (p *TracerProvider) ForceFlush(ctx context.Context) error { panic("stub") }
+func (p *TracerProvider) Shutdown(ctx context.Context) error   { panic("stub") }
@@ -0,0 +1,72 @@
+// Package trace is a type-only stub of go.opentelemetry.io/otel/trace.
+package trace
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type TraceID [16]byte
+
+func (t TraceID) String() string { panic("stub") }
+
+type SpanID [8]byte
+
+func (s SpanID) String() string { panic("stub") }
+
+type SpanContext struct{}
+
+func (sc SpanContext) TraceID() TraceID { panic("stub") }
+func (sc SpanContext) SpanID() SpanID   { panic("stub") }
+func (sc SpanContext) IsValid() bool    { panic("stub") }
+
+type SpanKind int
+
+const (
+	SpanKindUnspecified SpanKind = 0
+	SpanKindInternal    SpanKind = 1
+	SpanKindServer      SpanKind = 2
+	SpanKindClient      SpanKind = 3
+	SpanKindProducer    SpanKind = 4
+	SpanKindConsumer    SpanKind = 5
+)
+
+type SpanStartOption interface{ applySpanStart() }
+type SpanEndOption interface{ applySpanEnd() }
+type EventOption interface{ applyEvent() }
+type TracerOption interface{ applyTracer() }
+
+type SpanStartEventOption interface {
+	SpanStartOption
+	EventOption
+}
+
+func WithSpanKind(kind SpanKind) SpanStartOption                           { panic("stub") }
+func WithAttributes(attributes ...attribute.KeyValue) SpanStartEventOption { panic("stub") }
+
+type Span interface {
+	End(options ...SpanEndOption)
+	AddEvent(name string, options ...EventOption)
+	IsRecording() bool
+	RecordError(err error, options ...EventOption)
+	SpanContext() SpanContext
+	SetStatus(code codes.Code, description string)
+	SetName(name string)
+	SetAttributes(kv ...attribute.KeyValue)
+}
+
+type Tracer interface {
+	Start(ctx context.Context, spanName string, opts ...SpanStartOption) (context.Context, Span)
+}
+
+type TracerProvider interface {
+	Tracer(name string, options ...TracerOption) Tracer
+}
+
+func SpanFromContext(ctx context.Context) Span               { panic("stub") }
+func SpanContextFromContext(ctx context.Context) SpanContext { panic("stub") }
+func ContextWithSpan(parent context.Context, span Span) context.Context {
+	panic("stub")
+}
@@ -0,0 +1,35 @@
+// Package fx is a type-only stub of go.uber.org/fx.
+package fx
+
+import "context"
+
+type Option interface{}
+
+func Provide(constructors ...interface{}) Option { panic("stub") }
+func Invoke(funcs ...interface{}) Option         { panic("stub") }
+func Supply(values ...interface{}) Option        { panic("stub") }
+
+type App struct{}
+
+func New(opts ...Option) *App { panic("stub") }
+
+func (app *App) Run()                            { panic("stub") }
+func (app *App) Start(ctx context.Context) error { panic("stub") }
+func (app *App) Stop(ctx context.Context) error  { panic("stub") }
+
+type Hook struct {
+	OnStart func(context.Context) error
+	OnStop  func(context.Context) error
+}
+
+type Lifecycle interface {
+	Append(Hook)
+}
+
+type ShutdownOption interface{}
+
+func ExitCode(code int) ShutdownOption { panic("stub") }
+
+type Shutdowner interface {
+	Shutdown(...ShutdownOption) error
+}
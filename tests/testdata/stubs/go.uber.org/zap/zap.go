@@ -0,0 +1,54 @@
+// Package zap is a type-only stub of go.uber.org/zap.
+package zap
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+type Option interface{}
+
+type Field struct{}
+
+func String(key string, val string) Field          { panic("stub") }
+func Int(key string, val int) Field                { panic("stub") }
+func Int64(key string, val int64) Field            { panic("stub") }
+func Bool(key string, val bool) Field              { panic("stub") }
+func Duration(key string, val time.Duration) Field { panic("stub") }
+func Error(err error) Field                        { panic("stub") }
+func Any(key string, value interface{}) Field      { panic("stub") }
+func ByteString(key string, val []byte) Field      { panic("stub") }
+
+type Logger struct{}
+
+func NewProduction(options ...Option) (*Logger, error)  { panic("stub") }
+func NewDevelopment(options ...Option) (*Logger, error) { panic("stub") }
+func NewNop() *Logger                                   { panic("stub") }
+
+func (log *Logger) With(fields ...Field) *Logger      { panic("stub") }
+func (log *Logger) Debug(msg string, fields ...Field) { panic("stub") }
+func (log *Logger) Info(msg string, fields ...Field)  { panic("stub") }
+func (log *Logger) Warn(msg string, fields ...Field)  { panic("stub") }
+func (log *Logger) Error(msg string, fields ...Field) { panic("stub") }
+func (log *Logger) Fatal(msg string, fields ...Field) { panic("stub") }
+func (log *Logger) Sync() error                       { panic("stub") }
+
+type AtomicLevel struct{}
+
+func NewAtomicLevelAt(l zapcore.Level) AtomicLevel { panic("stub") }
+
+type Config struct {
+	Level            AtomicLevel
+	Development      bool
+	Encoding         string
+	EncoderConfig    zapcore.EncoderConfig
+	OutputPaths      []string
+	ErrorOutputPaths []string
+}
+
+func NewProductionConfig() Config                        { panic("stub") }
+func NewDevelopmentConfig() Config                       { panic("stub") }
+func NewProductionEncoderConfig() zapcore.EncoderConfig  { panic("stub") }
+func NewDevelopmentEncoderConfig() zapcore.EncoderConfig { panic("stub") }
+func (cfg Config) Build(opts ...Option) (*Logger, error) { panic("stub") }
@@ -0,0 +1,12 @@
+// Package zapcore is a type-only stub of go.uber.org/zap/zapcore.
+package zapcore
+
+type Level int8
+
+func ParseLevel(text string) (Level, error) { panic("stub") }
+
+type EncoderConfig struct {
+	MessageKey string
+	LevelKey   string
+	TimeKey    string
+}
@@ -115,8 +115,8 @@ func TestPathOperations(t *testing.T) {
 			name:          "Hidden file",
 			path:          ".config",
 			wantDir:       ".",
-			wantBase:      ".config",
-			wantExtension: "",
+			wantBase:      "",
+			wantExtension: ".config",
 		},
 		{
 			name:          "Multiple extensions",
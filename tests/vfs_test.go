@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/go-sova/sova-cli/pkg/vfs"
+)
+
+func TestFilesystems(t *testing.T) {
+	testCases := []struct {
+		name  string
+		newFS func(t *testing.T) vfs.FS
+	}{
+		{
+			name: "OS",
+			newFS: func(t *testing.T) vfs.FS {
+				return vfs.NewOSFS(filepath.Join(t.TempDir(), "project"))
+			},
+		},
+		{
+			name: "Memory",
+			newFS: func(t *testing.T) vfs.FS {
+				return vfs.NewMemFS()
+			},
+		},
+		{
+			name: "Overlay",
+			newFS: func(t *testing.T) vfs.FS {
+				return vfs.NewOverlayFS(vfs.NewMemFS(), fstest.MapFS{})
+			},
+		},
+		{
+			name: "Limit",
+			newFS: func(t *testing.T) vfs.FS {
+				return vfs.NewLimitFS(vfs.NewMemFS(), 1024)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsys := tc.newFS(t)
+
+			if err := fsys.MkdirAll("cmd/app", 0755); err != nil {
+				t.Fatalf("Failed to create directory: %v", err)
+			}
+			if err := fsys.WriteFile("cmd/app/main.go", []byte("package main\n"), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+			if err := fsys.WriteFile("go.mod", []byte("module demo\n"), 0644); err != nil {
+				t.Fatalf("Failed to write file: %v", err)
+			}
+
+			if err := fsys.WriteFile("missing/file.go", nil, 0644); !errors.Is(err, fs.ErrNotExist) {
+				t.Errorf("Expected ErrNotExist writing into a missing directory, got %v", err)
+			}
+			if err := fsys.WriteFile("../escape.go", nil, 0644); err == nil {
+				t.Error("Expected an error writing outside the root")
+			}
+
+			content, err := fs.ReadFile(fsys, "cmd/app/main.go")
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			if string(content) != "package main\n" {
+				t.Errorf("File content mismatch. Got %q", content)
+			}
+
+			var paths []string
+			err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+				paths = append(paths, name)
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Failed to walk filesystem: %v", err)
+			}
+
+			want := []string{".", "cmd", "cmd/app", "cmd/app/main.go", "go.mod"}
+			if !reflect.DeepEqual(paths, want) {
+				t.Errorf("Walk mismatch. Want %v, got %v", want, paths)
+			}
+
+			if err := fstest.TestFS(fsys, "cmd/app/main.go", "go.mod"); err != nil {
+				t.Errorf("Filesystem does not satisfy io/fs semantics: %v", err)
+			}
+		})
+	}
+}
+
+func TestLimitFS(t *testing.T) {
+	mem := vfs.NewMemFS()
+	limited := vfs.NewLimitFS(mem, 10)
+
+	if err := limited.WriteFile("a", []byte("123456"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := limited.WriteFile("a", []byte("12345678"), 0644); err != nil {
+		t.Fatalf("Failed to rewrite file within the limit: %v", err)
+	}
+	if limited.Exceeded() {
+		t.Error("Expected the limit not to be exceeded yet")
+	}
+
+	err := limited.WriteFile("b", []byte("123"), 0644)
+	if !errors.Is(err, vfs.ErrTooLarge) {
+		t.Errorf("Expected ErrTooLarge past the limit, got %v", err)
+	}
+	if !limited.Exceeded() {
+		t.Error("Expected the limit to be exceeded")
+	}
+	if vfs.Exists(mem, "b") {
+		t.Error("Expected the write past the limit not to reach the filesystem")
+	}
+	if mem.Size() != 8 {
+		t.Errorf("Expected 8 bytes written, got %d", mem.Size())
+	}
+}
+
+func TestOverlayFS(t *testing.T) {
+	lowerDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(lowerDir, "internal"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(lowerDir, "internal", "existing.go"), []byte("lower"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	upper := vfs.NewMemFS()
+	overlay := vfs.NewOverlayFS(upper, os.DirFS(lowerDir))
+
+	if err := overlay.WriteFile("internal/added.go", []byte("upper"), 0644); err != nil {
+		t.Fatalf("Failed to write through a lower-layer directory: %v", err)
+	}
+	if err := overlay.WriteFile("internal/existing.go", []byte("replaced"), 0644); err != nil {
+		t.Fatalf("Failed to shadow a lower-layer file: %v", err)
+	}
+
+	entries, err := fs.ReadDir(overlay, "internal")
+	if err != nil {
+		t.Fatalf("Failed to read merged directory: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name() != "added.go" || entries[1].Name() != "existing.go" {
+		t.Errorf("Unexpected merged entries: %v", entries)
+	}
+
+	content, err := fs.ReadFile(overlay, "internal/existing.go")
+	if err != nil || string(content) != "replaced" {
+		t.Errorf("Expected the upper layer to win, got %q (%v)", content, err)
+	}
+
+	onDisk, err := os.ReadFile(filepath.Join(lowerDir, "internal", "existing.go"))
+	if err != nil || string(onDisk) != "lower" {
+		t.Errorf("Lower layer was modified: %q (%v)", onDisk, err)
+	}
+	if _, err := os.Stat(filepath.Join(lowerDir, "internal", "added.go")); !os.IsNotExist(err) {
+		t.Errorf("Write leaked into the lower layer: %v", err)
+	}
+}